@@ -0,0 +1,233 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	restfulV1 "github.com/emicklei/go-restful"
+	"github.com/emicklei/go-restful/v3"
+)
+
+const defaultAdminPath = defaultHealthCheckPath + "/admin"
+
+var (
+	errOverrideExpired        = errors.New("override expiry time must be in the future")
+	errOverrideReasonRequired = errors.New("override reason is required")
+)
+
+// Override forces the reported health of a dependency until it expires, e.g. during a planned maintenance.
+type Override struct {
+	Healthy   bool      `json:"healthy"`
+	Reason    string    `json:"reason"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SetOverride forces the reported health of a dependency until the override expires. The override must have a reason.
+func (h *healthCheck) SetOverride(name string, override Override) error {
+	if strings.TrimSpace(override.Reason) == "" {
+		return errOverrideReasonRequired
+	}
+
+	if !override.ExpiresAt.After(time.Now()) {
+		return errOverrideExpired
+	}
+
+	h.dependenciesMutex.Lock()
+	if _, exist := h.dependencies[name]; !exist {
+		h.dependenciesMutex.Unlock()
+
+		return errDependencyNotFound
+	}
+	h.overrides[name] = override
+	events := h.statusEventsLocked()
+	h.dependenciesMutex.Unlock()
+
+	h.notifyWatchers()
+	h.fireStatusChange(events)
+
+	return nil
+}
+
+// ClearOverride removes the override of a dependency, reporting its check result again.
+func (h *healthCheck) ClearOverride(name string) error {
+	h.dependenciesMutex.Lock()
+	if _, exist := h.dependencies[name]; !exist {
+		h.dependenciesMutex.Unlock()
+
+		return errDependencyNotFound
+	}
+	delete(h.overrides, name)
+	events := h.statusEventsLocked()
+	h.dependenciesMutex.Unlock()
+
+	h.notifyWatchers()
+	h.fireStatusChange(events)
+
+	return nil
+}
+
+// applyOverrideLocked returns the dependency with its active override applied. Expired overrides are ignored and
+// removed by the next statusEventsLocked. dependenciesMutex must be held by the caller.
+func (h *healthCheck) applyOverrideLocked(d healthDependency) healthDependency {
+	override, exist := h.overrides[d.Name]
+	if !exist || !override.ExpiresAt.After(time.Now()) {
+		return d
+	}
+
+	d.Healthy = override.Healthy
+	d.degraded = false
+	d.BlockedBy = ""
+	d.Overridden = true
+	d.OverrideReason = override.Reason
+	d.OverrideExpiresAt = &override.ExpiresAt
+
+	return d
+}
+
+// AddAdminWebservice returns the admin webservices to override the dependencies health.
+func (h *healthCheck) AddAdminWebservice(filters ...restful.FilterFunction) []*restful.WebService {
+	paths := []string{defaultAdminPath}
+	if h.basePath != "" {
+		paths = append(paths, h.basePath+defaultAdminPath)
+	}
+
+	webservices := make([]*restful.WebService, 0, len(paths))
+	for _, path := range paths {
+		webservice := new(restful.WebService)
+		webservice.Path(path)
+		for _, filter := range filters {
+			webservice.Filter(filter)
+		}
+
+		// route to http://example.com/healthz/admin/dependencies/{name}/override
+		webservice.Route(
+			webservice.PUT("/dependencies/{name}/override").
+				To(h.putOverrideV3).
+				Consumes(restful.MIME_JSON).
+				Param(webservice.PathParameter("name", "dependency name")).
+				Reads(Override{}))
+		webservice.Route(
+			webservice.DELETE("/dependencies/{name}/override").
+				To(h.deleteOverrideV3).
+				Param(webservice.PathParameter("name", "dependency name")))
+
+		webservices = append(webservices, webservice)
+	}
+
+	return webservices
+}
+
+// AddAdminWebserviceV1 is the go-restful v1 version of AddAdminWebservice.
+func (h *healthCheck) AddAdminWebserviceV1(filters ...restfulV1.FilterFunction) []*restfulV1.WebService {
+	paths := []string{defaultAdminPath}
+	if h.basePath != "" {
+		paths = append(paths, h.basePath+defaultAdminPath)
+	}
+
+	webservices := make([]*restfulV1.WebService, 0, len(paths))
+	for _, path := range paths {
+		webservice := new(restfulV1.WebService)
+		webservice.Path(path)
+		for _, filter := range filters {
+			webservice.Filter(filter)
+		}
+
+		// route to http://example.com/healthz/admin/dependencies/{name}/override
+		webservice.Route(webservice.PUT("/dependencies/{name}/override").
+			To(h.putOverrideV1).
+			Consumes(restful.MIME_JSON).
+			Param(webservice.PathParameter("name", "dependency name")).
+			Reads(Override{}))
+		webservice.Route(webservice.DELETE("/dependencies/{name}/override").
+			To(h.deleteOverrideV1).
+			Param(webservice.PathParameter("name", "dependency name")))
+
+		webservices = append(webservices, webservice)
+	}
+
+	return webservices
+}
+
+func (h *healthCheck) putOverrideV3(req *restful.Request, resp *restful.Response) {
+	override := Override{}
+	if err := req.ReadEntity(&override); err != nil {
+		h.writeAdminError(resp.WriteErrorString, http.StatusBadRequest, err)
+
+		return
+	}
+
+	if err := h.SetOverride(req.PathParameter("name"), override); err != nil {
+		h.writeAdminError(resp.WriteErrorString, adminErrorStatus(err), err)
+
+		return
+	}
+
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *healthCheck) deleteOverrideV3(req *restful.Request, resp *restful.Response) {
+	if err := h.ClearOverride(req.PathParameter("name")); err != nil {
+		h.writeAdminError(resp.WriteErrorString, adminErrorStatus(err), err)
+
+		return
+	}
+
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *healthCheck) putOverrideV1(req *restfulV1.Request, resp *restfulV1.Response) {
+	override := Override{}
+	if err := req.ReadEntity(&override); err != nil {
+		h.writeAdminError(resp.WriteErrorString, http.StatusBadRequest, err)
+
+		return
+	}
+
+	if err := h.SetOverride(req.PathParameter("name"), override); err != nil {
+		h.writeAdminError(resp.WriteErrorString, adminErrorStatus(err), err)
+
+		return
+	}
+
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *healthCheck) deleteOverrideV1(req *restfulV1.Request, resp *restfulV1.Response) {
+	if err := h.ClearOverride(req.PathParameter("name")); err != nil {
+		h.writeAdminError(resp.WriteErrorString, adminErrorStatus(err), err)
+
+		return
+	}
+
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *healthCheck) writeAdminError(write func(int, string) error, status int, err error) {
+	if writeErr := write(status, err.Error()); writeErr != nil {
+		h.logger.Error("Unable to write admin response", "error", writeErr)
+	}
+}
+
+func adminErrorStatus(err error) int {
+	if errors.Is(err, errDependencyNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusBadRequest
+}
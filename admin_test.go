@@ -0,0 +1,183 @@
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/emicklei/go-restful/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAdminServer(t *testing.T, h Handler) *httptest.Server {
+	t.Helper()
+
+	authFilter := func(req *restful.Request, resp *restful.Response, chain *restful.FilterChain) {
+		if req.HeaderParameter("Authorization") != "Bearer admin" {
+			_ = resp.WriteErrorString(http.StatusUnauthorized, "unauthorized")
+
+			return
+		}
+		chain.ProcessFilter(req, resp)
+	}
+
+	container := restful.NewContainer()
+	for _, webService := range h.AddWebservice() {
+		container.Add(webService)
+	}
+	for _, webService := range h.AddAdminWebservice(authFilter) {
+		container.Add(webService)
+	}
+
+	server := httptest.NewServer(container)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func callAdmin(t *testing.T, server *httptest.Server, method, path, token string, body interface{}) int {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(payload))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", restful.MIME_JSON)
+	req.Header.Set("Authorization", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func getHealthz(t *testing.T, server *httptest.Server) (int, map[string]interface{}) {
+	t.Helper()
+
+	resp, err := http.Get(server.URL + "/healthz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	return resp.StatusCode, body
+}
+
+func Test_AdminOverride(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, BasePath: servicePath})
+	h.AddHardHealthCheck("elastic", testURL, func() error { return fmt.Errorf("error") })
+
+	server := newTestAdminServer(t, h)
+	path := "/healthz/admin/dependencies/elastic/override"
+	override := Override{Healthy: true, Reason: "planned upgrade", ExpiresAt: time.Now().Add(time.Hour)}
+
+	assert.Equal(t, http.StatusUnauthorized, callAdmin(t, server, http.MethodPut, path, "", override))
+	assert.Equal(t, http.StatusNotFound, callAdmin(t, server, http.MethodPut,
+		"/healthz/admin/dependencies/unknown/override", "Bearer admin", override))
+	assert.Equal(t, http.StatusBadRequest, callAdmin(t, server, http.MethodPut, path, "Bearer admin",
+		Override{Healthy: true, Reason: "planned upgrade", ExpiresAt: time.Now().Add(-time.Hour)}))
+	// an override without a reason is rejected
+	assert.Equal(t, http.StatusBadRequest, callAdmin(t, server, http.MethodPut, path, "Bearer admin",
+		Override{Healthy: true, ExpiresAt: time.Now().Add(time.Hour)}))
+	assert.Equal(t, http.StatusBadRequest, callAdmin(t, server, http.MethodPut, path, "Bearer admin",
+		Override{Healthy: true, Reason: " ", ExpiresAt: time.Now().Add(time.Hour)}))
+
+	code, _ := getHealthz(t, server)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	assert.Equal(t, http.StatusNoContent, callAdmin(t, server, http.MethodPut, servicePath+path, "Bearer admin",
+		override))
+
+	code, body := getHealthz(t, server)
+	assert.Equal(t, http.StatusOK, code)
+	dependencies := body["dependencies"].([]interface{})
+	require.Len(t, dependencies, 1)
+	dependency := dependencies[0].(map[string]interface{})
+	assert.Equal(t, true, dependency["healthy"])
+	assert.Equal(t, true, dependency["overridden"])
+	assert.Equal(t, "planned upgrade", dependency["overrideReason"])
+
+	assert.Equal(t, http.StatusUnauthorized, callAdmin(t, server, http.MethodDelete, path, "", nil))
+	assert.Equal(t, http.StatusNoContent, callAdmin(t, server, http.MethodDelete, path, "Bearer admin", nil))
+
+	code, body = getHealthz(t, server)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	dependency = body["dependencies"].([]interface{})[0].(map[string]interface{})
+	assert.Nil(t, dependency["overridden"])
+}
+
+func Test_AdminOverrideExpiry(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("elastic", testURL, func() error { return nil })
+
+	server := newTestAdminServer(t, h)
+	assert.Equal(t, http.StatusNoContent, callAdmin(t, server, http.MethodPut,
+		"/healthz/admin/dependencies/elastic/override", "Bearer admin",
+		Override{Healthy: false, Reason: "out of rotation", ExpiresAt: time.Now().Add(100 * time.Millisecond)}))
+
+	code, _ := getHealthz(t, server)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	time.Sleep(150 * time.Millisecond)
+
+	code, body := getHealthz(t, server)
+	assert.Equal(t, http.StatusOK, code)
+	dependency := body["dependencies"].([]interface{})[0].(map[string]interface{})
+	assert.Nil(t, dependency["overridden"])
+}
+
+func Test_AdminOverrideStatusChange(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("elastic", testURL, func() error { return fmt.Errorf("error") })
+	h.(*healthCheck).runChecks(context.Background())
+
+	var events []StatusChangeEvent
+	h.OnStatusChange(func(evt StatusChangeEvent) {
+		events = append(events, evt)
+	})
+
+	require.NoError(t, h.SetOverride("elastic",
+		Override{Healthy: true, Reason: "planned upgrade", ExpiresAt: time.Now().Add(time.Hour)}))
+	require.Len(t, events, 2)
+	assert.Equal(t, "elastic", events[0].Dependency)
+	assert.Equal(t, StatusFail, events[0].PreviousStatus)
+	assert.Equal(t, StatusPass, events[0].Status)
+	assert.Equal(t, "", events[1].Dependency)
+	assert.Equal(t, StatusPass, events[1].Status)
+
+	// checks keep failing, the override still applies
+	events = nil
+	h.(*healthCheck).runChecks(context.Background())
+	assert.Empty(t, events)
+
+	dependencies := h.Dependencies()
+	require.Len(t, dependencies, 1)
+	assert.True(t, dependencies[0].Healthy)
+
+	events = nil
+	require.NoError(t, h.ClearOverride("elastic"))
+	require.Len(t, events, 2)
+	assert.Equal(t, StatusFail, events[0].Status)
+
+	events = nil
+	require.NoError(t, h.SetOverride("elastic",
+		Override{Healthy: true, Reason: "planned upgrade", ExpiresAt: time.Now().Add(50 * time.Millisecond)}))
+	require.Len(t, events, 2)
+
+	// the expired override is removed by the next result
+	time.Sleep(100 * time.Millisecond)
+	events = nil
+	h.(*healthCheck).runChecks(context.Background())
+	require.Len(t, events, 2)
+	assert.Equal(t, StatusFail, events[0].Status)
+	assert.Empty(t, h.(*healthCheck).overrides)
+}
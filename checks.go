@@ -35,12 +35,24 @@ var errClientNil = fmt.Errorf("client is nil")
 
 // MongoHealthCheck is function for mongodb health check
 func MongoHealthCheck(mongoClient *mongo.Client, timeout time.Duration, additionalCheck ...func(mongoClient *mongo.Client) error) CheckFunc {
-	return func() error {
+	additionalCheckWithContext := make([]func(ctx context.Context, mongoClient *mongo.Client) error, len(additionalCheck))
+	for i, f := range additionalCheck {
+		f := f
+		additionalCheckWithContext[i] = func(_ context.Context, mongoClient *mongo.Client) error { return f(mongoClient) }
+	}
+
+	return withoutContext(MongoHealthCheckWithContext(mongoClient, timeout, additionalCheckWithContext...))
+}
+
+// MongoHealthCheckWithContext is function for mongodb health check honouring the context of the check run
+func MongoHealthCheckWithContext(mongoClient *mongo.Client, timeout time.Duration,
+	additionalCheck ...func(ctx context.Context, mongoClient *mongo.Client) error) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if mongoClient == nil {
 			return errClientNil
 		}
 
-		ctxWithTimeout, ctxWithTimeoutCancel := context.WithTimeout(context.Background(), timeout)
+		ctxWithTimeout, ctxWithTimeoutCancel := context.WithTimeout(ctx, timeout)
 		defer ctxWithTimeoutCancel()
 
 		err := mongoClient.Ping(ctxWithTimeout, nil)
@@ -49,7 +61,7 @@ func MongoHealthCheck(mongoClient *mongo.Client, timeout time.Duration, addition
 		}
 
 		for _, f := range additionalCheck {
-			err = f(mongoClient)
+			err = f(ctxWithTimeout, mongoClient)
 			if err != nil {
 				return err
 			}
@@ -62,7 +74,13 @@ func MongoHealthCheck(mongoClient *mongo.Client, timeout time.Duration, addition
 // IamHealthCheck is function for IAM health check. The requiredClientPermissions parameter is an optional parameter to
 // check if the IAM client token has the specified permissions.
 func IamHealthCheck(iamClient iam.Client, requiredClientPermissions []iam.Permission) CheckFunc {
-	return func() error {
+	return withoutContext(IamHealthCheckWithContext(iamClient, requiredClientPermissions))
+}
+
+// IamHealthCheckWithContext is function for IAM health check honouring the context of the check run. Since the IAM
+// client does not accept a context, the check stops in between the IAM calls once the context is done.
+func IamHealthCheckWithContext(iamClient iam.Client, requiredClientPermissions []iam.Permission) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if iamClient == nil {
 			return errClientNil
 		}
@@ -78,6 +96,10 @@ func IamHealthCheck(iamClient iam.Client, requiredClientPermissions []iam.Permis
 			}
 
 			for _, p := range requiredClientPermissions {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
 				allowed, err := iamClient.ValidatePermission(clientJWT, p, map[string]string{"{namespace}": clientJWT.Namespace})
 				if err != nil {
 					return fmt.Errorf("IAM is unhealthy: %s", err.Error())
@@ -94,12 +116,24 @@ func IamHealthCheck(iamClient iam.Client, requiredClientPermissions []iam.Permis
 
 // RedisHealthCheck is function for Redis health check
 func RedisHealthCheck(redisClient *redis.Client, timeout time.Duration, additionalCheck ...func(redisClient *redis.Client) error) CheckFunc {
-	return func() error {
+	additionalCheckWithContext := make([]func(ctx context.Context, redisClient *redis.Client) error, len(additionalCheck))
+	for i, f := range additionalCheck {
+		f := f
+		additionalCheckWithContext[i] = func(_ context.Context, redisClient *redis.Client) error { return f(redisClient) }
+	}
+
+	return withoutContext(RedisHealthCheckWithContext(redisClient, timeout, additionalCheckWithContext...))
+}
+
+// RedisHealthCheckWithContext is function for Redis health check honouring the context of the check run
+func RedisHealthCheckWithContext(redisClient *redis.Client, timeout time.Duration,
+	additionalCheck ...func(ctx context.Context, redisClient *redis.Client) error) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if redisClient == nil {
 			return errClientNil
 		}
 
-		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), timeout)
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
 		err := redisClient.Ping(ctxWithTimeout).Err()
@@ -108,7 +142,7 @@ func RedisHealthCheck(redisClient *redis.Client, timeout time.Duration, addition
 		}
 
 		for _, f := range additionalCheck {
-			err = f(redisClient)
+			err = f(ctxWithTimeout, redisClient)
 			if err != nil {
 				return err
 			}
@@ -121,12 +155,28 @@ func RedisHealthCheck(redisClient *redis.Client, timeout time.Duration, addition
 // UniversalRedisHealthCheck is function for Redis health check using Universal Redis (support cluster and standalone)
 func UniversalRedisHealthCheck(redisClient redis.UniversalClient, timeout time.Duration,
 	additionalCheck ...func(redisClient redis.UniversalClient) error) CheckFunc {
-	return func() error {
+	additionalCheckWithContext := make([]func(ctx context.Context, redisClient redis.UniversalClient) error,
+		len(additionalCheck))
+	for i, f := range additionalCheck {
+		f := f
+		additionalCheckWithContext[i] = func(_ context.Context, redisClient redis.UniversalClient) error {
+			return f(redisClient)
+		}
+	}
+
+	return withoutContext(UniversalRedisHealthCheckWithContext(redisClient, timeout, additionalCheckWithContext...))
+}
+
+// UniversalRedisHealthCheckWithContext is function for Redis health check using Universal Redis (support cluster and
+// standalone) honouring the context of the check run
+func UniversalRedisHealthCheckWithContext(redisClient redis.UniversalClient, timeout time.Duration,
+	additionalCheck ...func(ctx context.Context, redisClient redis.UniversalClient) error) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if redisClient == nil {
 			return errClientNil
 		}
 
-		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), timeout)
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
 		err := redisClient.Ping(ctxWithTimeout).Err()
@@ -135,7 +185,7 @@ func UniversalRedisHealthCheck(redisClient redis.UniversalClient, timeout time.D
 		}
 
 		for _, f := range additionalCheck {
-			err = f(redisClient)
+			err = f(ctxWithTimeout, redisClient)
 			if err != nil {
 				return err
 			}
@@ -147,12 +197,17 @@ func UniversalRedisHealthCheck(redisClient redis.UniversalClient, timeout time.D
 
 // ElasticHealthCheck is function for Elastic health check
 func ElasticHealthCheck(elasticClient *elastic.Client, host, port string, timeout time.Duration) CheckFunc {
-	return func() error {
+	return withoutContext(ElasticHealthCheckWithContext(elasticClient, host, port, timeout))
+}
+
+// ElasticHealthCheckWithContext is function for Elastic health check honouring the context of the check run
+func ElasticHealthCheckWithContext(elasticClient *elastic.Client, host, port string, timeout time.Duration) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if elasticClient == nil {
 			return fmt.Errorf("unable to ping elastic search: client is nil")
 		}
 
-		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), timeout)
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
 		res, code, err := elasticClient.Ping(fmt.Sprintf("%s:%s", host, port)).Do(ctxWithTimeout)
@@ -187,7 +242,20 @@ func ElasticHealthCheck(elasticClient *elastic.Client, host, port string, timeou
 
 // PostgresHealthCheck is health check for Postgres with gorm V2 driver
 func PostgresHealthCheck(postgreClient *gorm.DB, timeout time.Duration, additionalCheck ...func(postgreClient *gorm.DB) error) CheckFunc {
-	return func() error {
+	additionalCheckWithContext := make([]func(ctx context.Context, postgreClient *gorm.DB) error, len(additionalCheck))
+	for i, f := range additionalCheck {
+		f := f
+		additionalCheckWithContext[i] = func(_ context.Context, postgreClient *gorm.DB) error { return f(postgreClient) }
+	}
+
+	return withoutContext(PostgresHealthCheckWithContext(postgreClient, timeout, additionalCheckWithContext...))
+}
+
+// PostgresHealthCheckWithContext is health check for Postgres with gorm V2 driver honouring the context of the
+// check run
+func PostgresHealthCheckWithContext(postgreClient *gorm.DB, timeout time.Duration,
+	additionalCheck ...func(ctx context.Context, postgreClient *gorm.DB) error) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if postgreClient == nil {
 			return errClientNil
 		}
@@ -197,7 +265,7 @@ func PostgresHealthCheck(postgreClient *gorm.DB, timeout time.Duration, addition
 			return fmt.Errorf("unable to get postgres database: %v", err)
 		}
 
-		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), timeout)
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
 		if err := db.PingContext(ctxWithTimeout); err != nil {
@@ -205,7 +273,7 @@ func PostgresHealthCheck(postgreClient *gorm.DB, timeout time.Duration, addition
 		}
 
 		for _, f := range additionalCheck {
-			err = f(postgreClient)
+			err = f(ctxWithTimeout, postgreClient)
 			if err != nil {
 				return err
 			}
@@ -217,12 +285,25 @@ func PostgresHealthCheck(postgreClient *gorm.DB, timeout time.Duration, addition
 
 // PostgresHealthCheckV1 is health check for Postgres with gorm V1 driver
 func PostgresHealthCheckV1(postgreClient *gormv1.DB, timeout time.Duration, additionalCheck ...func(postgreClient *gormv1.DB) error) CheckFunc {
-	return func() error {
+	additionalCheckWithContext := make([]func(ctx context.Context, postgreClient *gormv1.DB) error, len(additionalCheck))
+	for i, f := range additionalCheck {
+		f := f
+		additionalCheckWithContext[i] = func(_ context.Context, postgreClient *gormv1.DB) error { return f(postgreClient) }
+	}
+
+	return withoutContext(PostgresHealthCheckV1WithContext(postgreClient, timeout, additionalCheckWithContext...))
+}
+
+// PostgresHealthCheckV1WithContext is health check for Postgres with gorm V1 driver honouring the context of the
+// check run
+func PostgresHealthCheckV1WithContext(postgreClient *gormv1.DB, timeout time.Duration,
+	additionalCheck ...func(ctx context.Context, postgreClient *gormv1.DB) error) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if postgreClient == nil {
 			return errClientNil
 		}
 
-		ctxWithTimeout, cancel := context.WithTimeout(context.Background(), timeout)
+		ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
 
 		if err := postgreClient.DB().PingContext(ctxWithTimeout); err != nil {
@@ -230,7 +311,7 @@ func PostgresHealthCheckV1(postgreClient *gormv1.DB, timeout time.Duration, addi
 		}
 
 		for _, f := range additionalCheck {
-			err := f(postgreClient)
+			err := f(ctxWithTimeout, postgreClient)
 			if err != nil {
 				return err
 			}
@@ -242,14 +323,30 @@ func PostgresHealthCheckV1(postgreClient *gormv1.DB, timeout time.Duration, addi
 
 // CloudStorageCheck is function for check cloud straoge health based on AccelByte common-blob-go library
 func CloudStorageCheck(cloudStorage commonblobgo.CloudStorage, additionalCheck ...func(cloudStorage commonblobgo.CloudStorage) error) CheckFunc {
-	return func() error {
+	additionalCheckWithContext := make([]func(ctx context.Context, cloudStorage commonblobgo.CloudStorage) error,
+		len(additionalCheck))
+	for i, f := range additionalCheck {
+		f := f
+		additionalCheckWithContext[i] = func(_ context.Context, cloudStorage commonblobgo.CloudStorage) error {
+			return f(cloudStorage)
+		}
+	}
+
+	return withoutContext(CloudStorageCheckWithContext(cloudStorage, additionalCheckWithContext...))
+}
+
+// CloudStorageCheckWithContext is function for check cloud storage health based on AccelByte common-blob-go library
+// honouring the context of the check run
+func CloudStorageCheckWithContext(cloudStorage commonblobgo.CloudStorage,
+	additionalCheck ...func(ctx context.Context, cloudStorage commonblobgo.CloudStorage) error) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if cloudStorage == nil {
 			return errClientNil
 		}
 
 		// get attribute of random key, if error returns is other than error not found, meaning there's
 		// an error at bucket provider service
-		_, err := cloudStorage.Get(context.Background(), "randomKey")
+		_, err := cloudStorage.Get(ctx, "randomKey")
 		if gcerrors.Code(err) == gcerrors.NotFound || err == nil {
 			return nil
 		}
@@ -258,7 +355,7 @@ func CloudStorageCheck(cloudStorage commonblobgo.CloudStorage, additionalCheck .
 		}
 
 		for _, f := range additionalCheck {
-			err := f(cloudStorage)
+			err := f(ctx, cloudStorage)
 			if err != nil {
 				return err
 			}
@@ -270,13 +367,42 @@ func CloudStorageCheck(cloudStorage commonblobgo.CloudStorage, additionalCheck .
 
 // KafkaEventstreamV4HealthCheck is health check for Kafka with eventstream-go-sdk v4 library.
 func KafkaEventstreamV4HealthCheck(client eventstream.Client, topic string, timeout time.Duration) CheckFunc {
-	return func() error {
+	return withoutContext(KafkaEventstreamV4HealthCheckWithContext(client, topic, timeout))
+}
+
+// KafkaEventstreamV4HealthCheckWithContext is health check for Kafka with eventstream-go-sdk v4 library honouring
+// the context of the check run. Since the eventstream client does not accept a context, the metadata request timeout
+// is shortened to the context deadline.
+func KafkaEventstreamV4HealthCheckWithContext(client eventstream.Client, topic string, timeout time.Duration) CheckFuncWithContext {
+	return func(ctx context.Context) error {
 		if client == nil {
 			return errClientNil
 		}
 
-		_, err := client.GetMetadata(topic, timeout)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		metadataTimeout := timeout
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return context.DeadlineExceeded
+			}
+			if remaining < metadataTimeout {
+				metadataTimeout = remaining
+			}
+		}
+
+		_, err := client.GetMetadata(topic, metadataTimeout)
 
 		return err
 	}
 }
+
+// withoutContext adapts a CheckFuncWithContext into a CheckFunc for the templates keeping the CheckFunc API.
+func withoutContext(f CheckFuncWithContext) CheckFunc {
+	return func() error {
+		return f(context.Background())
+	}
+}
@@ -200,3 +200,32 @@ func TestKafkaEventstreamV4HealthCheck(t *testing.T) {
 	assert.Nil(t, KafkaEventstreamV4HealthCheck(client, "myTopic", time.Second)())
 	assert.NotNil(t, KafkaEventstreamV4HealthCheck(client, "errorTopic", time.Second)())
 }
+
+func TestKafkaEventstreamV4HealthCheckWithContext(t *testing.T) {
+	client := &eventStreamMock{}
+
+	assert.Nil(t, KafkaEventstreamV4HealthCheckWithContext(client, "myTopic", time.Second)(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, KafkaEventstreamV4HealthCheckWithContext(client, "myTopic", time.Second)(ctx))
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded,
+		KafkaEventstreamV4HealthCheckWithContext(client, "myTopic", time.Second)(ctx))
+}
+
+func TestRedisHealthCheckWithContext(t *testing.T) {
+	assert.Error(t, RedisHealthCheckWithContext(nil, timeout)(context.Background()))
+
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     "localhost:6379",
+		Password: "redispass",
+	})
+	assert.Nil(t, RedisHealthCheckWithContext(redisClient, timeout)(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, RedisHealthCheckWithContext(redisClient, timeout)(ctx))
+}
@@ -0,0 +1,61 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+// createsCycleLocked returns whether registering the dependency with the upstream dependencies creates a cycle.
+// Upstream dependencies which are not registered yet are followed once they are registered.
+// dependenciesMutex must be held by the caller.
+func (h *healthCheck) createsCycleLocked(name string, dependsOn []string) bool {
+	visited := make(map[string]bool)
+
+	var reaches func(upstreams []string) bool
+	reaches = func(upstreams []string) bool {
+		for _, upstream := range upstreams {
+			if upstream == name {
+				return true
+			}
+			if visited[upstream] {
+				continue
+			}
+			visited[upstream] = true
+
+			if d, exist := h.dependencies[upstream]; exist && reaches(d.DependsOn) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return reaches(dependsOn)
+}
+
+// blockingUpstreamLocked returns the first upstream dependency which is unhealthy, blocking the dependency check.
+// An upstream dependency which has never been checked does not block. dependenciesMutex must be held by the caller.
+func (h *healthCheck) blockingUpstreamLocked(d healthDependency) string {
+	for _, name := range d.DependsOn {
+		upstream, exist := h.dependencies[name]
+		if !exist {
+			continue
+		}
+
+		upstream = h.reportedLocked(upstream)
+		if (upstream.LastCall != nil || upstream.Overridden) && !upstream.Healthy {
+			return name
+		}
+	}
+
+	return ""
+}
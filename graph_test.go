@@ -0,0 +1,86 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DependsOn(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+
+	var proxyErr error
+	var iamChecks int32
+	h.AddHardHealthCheck("iam", testURL, func() error {
+		atomic.AddInt32(&iamChecks, 1)
+		return nil
+	}, WithDependsOn("proxy"))
+	h.AddHealthCheck("proxy", testURL, func() error { return proxyErr })
+
+	var events []StatusChangeEvent
+	h.OnStatusChange(func(evt StatusChangeEvent) {
+		events = append(events, evt)
+	})
+
+	code, _ := h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&iamChecks))
+
+	// the upstream fails in the same run, hence the dependency check is skipped right away and the blocked hard
+	// dependency fails the service
+	proxyErr = fmt.Errorf("connection refused")
+	code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFail, resp.Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&iamChecks))
+
+	dependencies := make(map[string]healthDependency)
+	for _, d := range resp.Dependencies {
+		dependencies[d.Name] = d
+	}
+	assert.Equal(t, StatusSkipped, dependencies["iam"].Status)
+	assert.Equal(t, "proxy", dependencies["iam"].BlockedBy)
+	assert.False(t, dependencies["iam"].Healthy)
+	assert.Equal(t, StatusWarn, dependencies["proxy"].Status)
+
+	var iamEvent *StatusChangeEvent
+	for i := range events {
+		if events[i].Dependency == "iam" {
+			iamEvent = &events[i]
+		}
+	}
+	require.NotNil(t, iamEvent)
+	assert.Equal(t, StatusSkipped, iamEvent.Status)
+	assert.Equal(t, "blocked by proxy", iamEvent.Error)
+
+	proxyErr = nil
+	_, resp = h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, StatusPass, resp.Status)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&iamChecks))
+	for _, d := range resp.Dependencies {
+		assert.Empty(t, d.BlockedBy)
+	}
+}
+
+func Test_DependsOnCycle(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHealthCheck("a", testURL, func() error { return nil }, WithDependsOn("c"))
+	h.AddHealthCheck("b", testURL, func() error { return nil }, WithDependsOn("a"))
+	h.AddHealthCheck("c", testURL, func() error { return nil }, WithDependsOn("b"))
+	h.AddHealthCheck("d", testURL, func() error { return nil }, WithDependsOn("d"))
+
+	dependencies := h.(*healthCheck).copyDependencies()
+	assert.Equal(t, []string{"c"}, dependencies["a"].DependsOn)
+	assert.Equal(t, []string{"a"}, dependencies["b"].DependsOn)
+	assert.Empty(t, dependencies["c"].DependsOn)
+	assert.Empty(t, dependencies["d"].DependsOn)
+
+	// the run does not deadlock
+	code, _ := h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusOK, code)
+}
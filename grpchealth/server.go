@@ -0,0 +1,103 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpchealth serves the health check dependencies using the gRPC health checking protocol, see
+// https://github.com/grpc/grpc/blob/master/doc/health-checking.md.
+package grpchealth
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	healthcheck "github.com/AccelByte/healthcheck-go-sdk/v2"
+)
+
+// server implements grpc.health.v1.Health on top of the health check dependencies.
+type server struct {
+	grpc_health_v1.UnimplementedHealthServer
+
+	h healthcheck.Handler
+}
+
+// NewServer returns a grpc.health.v1.Health server backed by the health check dependencies. The empty service name
+// reports the overall service health and each dependency name can be queried as a service on its own.
+// Register it using grpc_health_v1.RegisterHealthServer.
+func NewServer(h healthcheck.Handler) grpc_health_v1.HealthServer {
+	return &server{h: h}
+}
+
+// Check returns the overall service health for the empty service name, otherwise the health of the dependency
+// with the same name as the requested service.
+func (s *server) Check(ctx context.Context,
+	req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	// if background health check worker is not running, check immediately
+	s.h.Refresh(ctx)
+
+	servingStatus := s.servingStatus(req.GetService())
+	if servingStatus == grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN {
+		return nil, status.Errorf(codes.NotFound, "unknown service %s", req.GetService())
+	}
+
+	return &grpc_health_v1.HealthCheckResponse{Status: servingStatus}, nil
+}
+
+// Watch streams the requested service health every time it changes. An unknown service is reported as
+// SERVICE_UNKNOWN and keeps being watched, as it may be registered later.
+func (s *server) Watch(req *grpc_health_v1.HealthCheckRequest,
+	stream grpc_health_v1.Health_WatchServer) error {
+	updated, unwatch := s.h.Watch()
+	defer unwatch()
+
+	lastStatus := grpc_health_v1.HealthCheckResponse_ServingStatus(-1)
+
+	for {
+		servingStatus := s.servingStatus(req.GetService())
+		if servingStatus != lastStatus {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: servingStatus}); err != nil {
+				return status.Error(codes.Canceled, "stream has ended")
+			}
+			lastStatus = servingStatus
+		}
+
+		select {
+		case <-updated:
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		}
+	}
+}
+
+func (s *server) servingStatus(service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if service == "" {
+		return toServingStatus(s.h.Healthy())
+	}
+
+	dependency, exist := s.h.Dependency(service)
+	if !exist {
+		return grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN
+	}
+
+	return toServingStatus(dependency.Healthy)
+}
+
+func toServingStatus(healthy bool) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if healthy {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
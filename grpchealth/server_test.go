@@ -0,0 +1,93 @@
+package grpchealth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	healthcheck "github.com/AccelByte/healthcheck-go-sdk/v2"
+)
+
+const (
+	testURL     = "www.test.example.com"
+	serviceName = "test"
+)
+
+func newTestGRPCClient(t *testing.T, h healthcheck.Handler) grpc_health_v1.HealthClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, NewServer(h))
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestServerCheck(t *testing.T) {
+	h := healthcheck.New(&healthcheck.Config{ServiceName: serviceName})
+	h.AddHealthCheck("soft", testURL, func() error { return fmt.Errorf("error") })
+	h.AddHardHealthCheck("hard", testURL, func() error { return nil })
+
+	client := newTestGRPCClient(t, h)
+
+	resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+
+	resp, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "soft"})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	resp, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "hard"})
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestServerWatch(t *testing.T) {
+	h := healthcheck.New(&healthcheck.Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("email", testURL, nil)
+	require.NoError(t, h.UpdateHealth("email", true, nil))
+
+	client := newTestGRPCClient(t, h)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+
+	require.NoError(t, h.UpdateHealth("email", false, &healthcheck.CheckError{Message: "error"}))
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
@@ -16,57 +16,186 @@ package healthcheck
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/rand"
 	"net/http"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	restfulV1 "github.com/emicklei/go-restful"
 	"github.com/emicklei/go-restful/v3"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
 	defaultHealthCheckPath = "/healthz"
+	defaultLivenessPath    = "/livez"
+	defaultReadinessPath   = "/readyz"
+	defaultStartupPath     = "/startupz"
+	dependencyPath         = "/dependencies"
 
 	DefaultBackgroundCheckInterval = 60 * time.Second
+	DefaultCheckTimeout            = 30 * time.Second
 )
 
+var errDependencyNotFound = errors.New("dependency name does not exist")
+
 type healthCheck struct {
 	serviceName       string
 	basePath          string
 	dependenciesMutex sync.RWMutex
 	dependencies      map[string]healthDependency
-	bgCheckRunning    bool
+	overrides         map[string]Override
+	rules             []quorumRule
+	bgCheckRunning    int32
 	bgCheckInterval   time.Duration
+	bgCheckJitter     time.Duration
+	scheduleCh        chan struct{}
+	checkTimeout      time.Duration
+	watchersMutex     sync.Mutex
+	watchers          map[chan struct{}]struct{}
+	observer          CheckObserver
+	inFlight          map[string]time.Time
+	statuses          map[string]Status
+	overallStatus     Status
+	listenersMutex    sync.RWMutex
+	listeners         []func(evt StatusChangeEvent)
+	logger            Logger
+	draining          int32
+	debug             bool
+	minCheckInterval  time.Duration
+	runGroup          singleflight.Group
+	lastRun           int64
+	sweepTimer        *time.Timer
 }
 
 type Config struct {
 	ServiceName             string
 	BasePath                string
 	BackgroundCheckInterval time.Duration
+	// BackgroundCheckJitter is the maximum random delay added to every background check interval, so that instances
+	// of the service do not check shared dependencies in lockstep.
+	BackgroundCheckJitter time.Duration
+	// CheckTimeout is the default time limit of a dependency check, can be overridden per dependency using WithTimeout.
+	CheckTimeout time.Duration
+	// Observer observes the check runs, e.g. otelhealth.NewObserver for OpenTelemetry spans and metrics.
+	Observer CheckObserver
+	// Logger defaults to the logrus standard logger, see NewSlogLogger and zaplogger.New for the other adapters.
+	Logger Logger
+	// Debug reports debug information in the health response, i.e. the stack of a panicking check in lastError.
+	Debug bool
+	// MinCheckInterval reuses the results of a check run for the health requests within the interval when the
+	// background check worker is not running, instead of checking the dependencies on every request.
+	MinCheckInterval time.Duration
 }
 
 type Handler interface {
+	// AddWebservice and AddWebserviceV1 register the /healthz endpoint and /healthz/dependencies/{name}, which reports
+	// a single dependency with a status code derived from that dependency alone. The tag query parameter, e.g.
+	// /healthz?tag=storage, only reports and computes the status of the dependencies registered with the tag.
 	AddWebservice() []*restful.WebService
 	AddWebserviceV1() []*restfulV1.WebService
 
+	// AddLivenessWebservice, AddReadinessWebservice and AddStartupWebservice register the /livez, /readyz and
+	// /startupz endpoints. Each of them only reports the dependencies registered for the corresponding probe
+	// using WithProbes, while /healthz keeps reporting every dependency.
+	AddLivenessWebservice() []*restful.WebService
+	AddLivenessWebserviceV1() []*restfulV1.WebService
+	AddReadinessWebservice() []*restful.WebService
+	AddReadinessWebserviceV1() []*restfulV1.WebService
+	AddStartupWebservice() []*restful.WebService
+	AddStartupWebserviceV1() []*restfulV1.WebService
+
+	// HTTPHandler returns a net/http handler serving /healthz, /healthz/dependencies/{name}, /livez, /readyz and
+	// /startupz, with and without the base path, for services that do not use go-restful.
+	HTTPHandler() http.Handler
+
+	// Refresh checks the dependencies like a health request does, unless the background check worker is running.
+	// It is used by the integrations, e.g. the gRPC health server of the grpchealth package.
+	Refresh(ctx context.Context)
+
+	// Healthy returns the overall service health as reported by /healthz, without checking the dependencies.
+	Healthy() bool
+
+	// Dependency returns the reported health of a dependency, without checking it.
+	Dependency(name string) (DependencyHealth, bool)
+
+	// Watch returns a channel receiving a signal every time the dependencies health might have changed. The returned
+	// function must be called to stop watching.
+	Watch() (<-chan struct{}, func())
+
+	// Dependencies returns the reported health of all dependencies sorted by name, without checking them.
+	Dependencies() []DependencyHealth
+
+	// ServiceName returns the configured service name.
+	ServiceName() string
+
 	// AddHealthCheck adds a dependency health check. It will be a soft dependency check, hence if the check failed,
 	// it will only return healthy=false on the corresponding dependency and will not affect the overall healthy status.
-	AddHealthCheck(name, url string, check CheckFunc)
+	AddHealthCheck(name, url string, check CheckFunc, opts ...DependencyOption)
 
 	// AddHardHealthCheck adds a hard dependency health check.
 	// It will return healthy=false on the corresponding dependency and the overall healthy status.
-	AddHardHealthCheck(name, url string, check CheckFunc)
+	AddHardHealthCheck(name, url string, check CheckFunc, opts ...DependencyOption)
+
+	// AddHealthCheckWithContext is the same as AddHealthCheck, but the check function receives the context of
+	// the check run.
+	AddHealthCheckWithContext(name, url string, check CheckFuncWithContext, opts ...DependencyOption)
+
+	// AddHardHealthCheckWithContext is the same as AddHardHealthCheck, but the check function receives the context
+	// of the check run.
+	AddHardHealthCheckWithContext(name, url string, check CheckFuncWithContext, opts ...DependencyOption)
 
 	// StartBackgroundCheck starts a background health check worker. The health check will be performed at a
-	// certain interval, specified in Config, rather than every health endpoint request.
+	// certain interval, specified in Config or per dependency using WithInterval, rather than every health endpoint
+	// request.
 	StartBackgroundCheck(ctx context.Context)
 
 	// UpdateHealth updates a dependency health status. If you want to exclusively update a dependency health
 	// using this, make sure to pass nil value onto check function param when adding the dependency using
 	// AddHealthCheck or AddHardHealthCheck.
 	UpdateHealth(name string, isHealthy bool, checkError *CheckError) error
+
+	// RecordResult records the result of a call to a dependency registered with WithErrorRate, which health is
+	// computed from the error rate of the results within a sliding window rather than the last result.
+	RecordResult(name string, err error) error
+
+	// OnStatusChange registers a listener called every time a dependency status or the overall service status
+	// changes, either from a check result or UpdateHealth. The first result of a dependency is not a change.
+	// Listeners are called synchronously by the check runner, hence should return quickly. They may be called
+	// concurrently from the check goroutines, hence must be safe for concurrent use.
+	OnStatusChange(listener func(evt StatusChangeEvent))
+
+	// SetDraining marks the service as draining, e.g. during a graceful shutdown. While draining, /healthz and the
+	// readiness probe return 503 with draining=true so the load balancer stops routing traffic, while the liveness
+	// probe stays healthy.
+	SetDraining(draining bool)
+
+	// Drain starts draining and waits for the delay before returning, giving the load balancer time to notice
+	// the failing readiness before the caller shuts down the server. It returns early when the context is done.
+	Drain(ctx context.Context, delay time.Duration) error
+
+	// AddAdminWebservice returns the admin webservices with PUT and DELETE
+	// /healthz/admin/dependencies/{name}/override to set and clear a dependency override. The filters, e.g.
+	// an authorization filter, are applied to every admin route.
+	AddAdminWebservice(filters ...restful.FilterFunction) []*restful.WebService
+	AddAdminWebserviceV1(filters ...restfulV1.FilterFunction) []*restfulV1.WebService
+
+	// SetOverride forces the reported health of a dependency, instead of its check result, until the override
+	// expires. The override must have a reason. ClearOverride removes it.
+	SetOverride(name string, override Override) error
+	ClearOverride(name string) error
+
+	// AddQuorumRule adds a named rule requiring at least minHealthy of the registered dependencies to be healthy,
+	// e.g. 2 of 3 redundant shards. An unmet rule fails the service status and the rule result is reported in the
+	// health response.
+	AddQuorumRule(name string, minHealthy int, dependencies ...string) error
 }
 
 func New(config *Config) Handler {
@@ -74,58 +203,123 @@ func New(config *Config) Handler {
 		config.BackgroundCheckInterval = DefaultBackgroundCheckInterval
 	}
 
+	if config.CheckTimeout <= 0 {
+		config.CheckTimeout = DefaultCheckTimeout
+	}
+
+	if config.Logger == nil {
+		config.Logger = NewLogrusLogger(logrus.StandardLogger())
+	}
+
+	if config.Observer == nil {
+		config.Observer = noopObserver{}
+	}
+
 	return &healthCheck{
 		serviceName:       config.ServiceName,
 		basePath:          config.BasePath,
 		dependenciesMutex: sync.RWMutex{},
 		dependencies:      make(map[string]healthDependency),
+		overrides:         make(map[string]Override),
 		bgCheckInterval:   config.BackgroundCheckInterval,
+		bgCheckJitter:     config.BackgroundCheckJitter,
+		scheduleCh:        make(chan struct{}, 1),
+		checkTimeout:      config.CheckTimeout,
+		watchers:          make(map[chan struct{}]struct{}),
+		inFlight:          make(map[string]time.Time),
+		statuses:          make(map[string]Status),
+		observer:          config.Observer,
+		logger:            config.Logger,
+		debug:             config.Debug,
+		minCheckInterval:  config.MinCheckInterval,
 	}
 }
 
 // AddHealthCheck adds a dependency health check. It will be a soft dependency check, hence if the check failed,
 // it will only return healthy=false on the corresponding dependency and will not affect the overall healthy status.
-func (h *healthCheck) AddHealthCheck(name, url string, check CheckFunc) {
-	h.dependenciesMutex.Lock()
-	defer h.dependenciesMutex.Unlock()
+func (h *healthCheck) AddHealthCheck(name, url string, check CheckFunc, opts ...DependencyOption) {
+	h.AddHealthCheckWithContext(name, url, check.withContext(), opts...)
+}
 
-	h.dependencies[name] = healthDependency{
+// AddHealthCheckWithContext adds a soft dependency health check which check function receives the context of
+// the check run.
+func (h *healthCheck) AddHealthCheckWithContext(name, url string, check CheckFuncWithContext, opts ...DependencyOption) {
+	h.addDependency(healthDependency{
 		Name:      name,
 		URL:       url,
 		checkFunc: check,
 		LastError: nil,
-	}
+	}, opts)
 }
 
 // AddHardHealthCheck adds a dependency hard health check.
 // It will return healthy=false on the corresponding dependency and the overall healthy status.
-func (h *healthCheck) AddHardHealthCheck(name, url string, check CheckFunc) {
-	h.dependenciesMutex.Lock()
-	defer h.dependenciesMutex.Unlock()
+func (h *healthCheck) AddHardHealthCheck(name, url string, check CheckFunc, opts ...DependencyOption) {
+	h.AddHardHealthCheckWithContext(name, url, check.withContext(), opts...)
+}
 
-	h.dependencies[name] = healthDependency{
+// AddHardHealthCheckWithContext adds a hard dependency health check which check function receives the context of
+// the check run.
+func (h *healthCheck) AddHardHealthCheckWithContext(name, url string, check CheckFuncWithContext,
+	opts ...DependencyOption) {
+	h.addDependency(healthDependency{
 		Name:           name,
 		URL:            url,
 		HardDependency: true,
 		checkFunc:      check,
 		LastError:      nil,
+	}, opts)
+}
+
+func (h *healthCheck) addDependency(dependency healthDependency, opts []DependencyOption) {
+	dependency.probes = []Probe{ProbeReadiness}
+	dependency.timeout = h.checkTimeout
+	dependency.interval = h.bgCheckInterval
+	dependency.failureThreshold = 1
+	dependency.successThreshold = 1
+	for _, opt := range opts {
+		opt(&dependency)
+	}
+
+	h.dependenciesMutex.Lock()
+	defer h.dependenciesMutex.Unlock()
+
+	if h.createsCycleLocked(dependency.Name, dependency.DependsOn) {
+		h.logger.Error("Dependency upstreams create a cycle, ignoring them", "dependency", dependency.Name,
+			"dependsOn", dependency.DependsOn)
+		dependency.DependsOn = nil
+	}
+
+	h.dependencies[dependency.Name] = dependency
+
+	// wake up the background check scheduler to check the new dependency
+	select {
+	case h.scheduleCh <- struct{}{}:
+	default:
 	}
 }
 
 // UpdateHealth updates a dependency health status.
 func (h *healthCheck) UpdateHealth(name string, isHealthy bool, checkError *CheckError) error {
 	h.dependenciesMutex.Lock()
-	defer h.dependenciesMutex.Unlock()
 
 	dependency, exist := h.dependencies[name]
 	if !exist {
-		return errors.New("dependency name does not exist")
+		h.dependenciesMutex.Unlock()
+
+		return errDependencyNotFound
 	}
 	dependency.Healthy = isHealthy
+	dependency.degraded = false
 	now := time.Now()
 	dependency.LastCall = &now
 	if isHealthy {
 		dependency.LastKnownGoodCall = dependency.LastCall
+		dependency.ConsecutiveSuccesses++
+		dependency.ConsecutiveFailures = 0
+	} else {
+		dependency.ConsecutiveFailures++
+		dependency.ConsecutiveSuccesses = 0
 	}
 	if checkError != nil {
 		dependency.LastError = &lastError{Message: checkError.Message}
@@ -133,23 +327,349 @@ func (h *healthCheck) UpdateHealth(name string, isHealthy bool, checkError *Chec
 			dependency.LastError.Timestamp = &checkError.Timestamp
 		}
 	}
-	h.dependencies[name] = dependency
+	events := h.storeDependencyLocked(dependency)
+	h.dependenciesMutex.Unlock()
+
+	h.notifyWatchers()
+	h.fireStatusChange(events)
 
 	return nil
 }
 
+// OnStatusChange registers a listener called every time a dependency status or the overall service status changes.
+func (h *healthCheck) OnStatusChange(listener func(evt StatusChangeEvent)) {
+	h.listenersMutex.Lock()
+	defer h.listenersMutex.Unlock()
+
+	h.listeners = append(h.listeners, listener)
+}
+
+// SetDraining marks the service as draining, failing /healthz and the readiness probe.
+func (h *healthCheck) SetDraining(draining bool) {
+	var value int32
+	if draining {
+		value = 1
+	}
+
+	if atomic.SwapInt32(&h.draining, value) != value {
+		h.notifyWatchers()
+	}
+}
+
+// Drain starts draining and waits for the delay before returning.
+func (h *healthCheck) Drain(ctx context.Context, delay time.Duration) error {
+	h.SetDraining(true)
+	h.logger.Info("Draining started", "delay", delay.String())
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *healthCheck) isDraining() bool {
+	return atomic.LoadInt32(&h.draining) == 1
+}
+
+// storeDependencyLocked stores the dependency and returns the resulting status change events.
+// dependenciesMutex must be held by the caller.
+func (h *healthCheck) storeDependencyLocked(d healthDependency) []StatusChangeEvent {
+	h.dependencies[d.Name] = d
+
+	return h.statusEventsLocked()
+}
+
+// statusEventsLocked compares the reported status of every dependency and the service with the last reported ones,
+// returning the changes. The first result of a dependency is not a change, so that a restart does not report every
+// dependency. dependenciesMutex must be held by the caller.
+func (h *healthCheck) statusEventsLocked() []StatusChangeEvent {
+	now := time.Now()
+
+	for name, override := range h.overrides {
+		if !override.ExpiresAt.After(now) {
+			delete(h.overrides, name)
+		}
+	}
+
+	var events []StatusChangeEvent
+
+	// dependencies which have never been checked are left out of the overall status
+	overallStatus := StatusPass
+	for name, d := range h.dependencies {
+		d = h.reportedLocked(d)
+		if d.LastCall == nil && !d.Overridden {
+			continue
+		}
+
+		status := d.status()
+		if previousStatus, exist := h.statuses[name]; exist && status != previousStatus {
+			event := StatusChangeEvent{
+				Dependency:     name,
+				PreviousStatus: previousStatus,
+				Status:         status,
+				Timestamp:      now,
+				HardDependency: d.HardDependency,
+			}
+			if status != StatusPass {
+				if d.Overridden {
+					event.Error = "overridden: " + d.OverrideReason
+				} else if d.BlockedBy != "" {
+					event.Error = "blocked by " + d.BlockedBy
+				} else if d.Stale {
+					event.Error = fmt.Sprintf("not updated within %s", d.staleAfter)
+				} else if d.LastError != nil {
+					event.Error = d.LastError.Message
+				}
+			}
+			events = append(events, event)
+		}
+		h.statuses[name] = status
+
+		if impact := d.impact(); impact.severity() > overallStatus.severity() {
+			overallStatus = impact
+		}
+	}
+
+	for _, rule := range h.rules {
+		if status := h.evaluateRuleLocked(rule).Status; status.severity() > overallStatus.severity() {
+			overallStatus = status
+		}
+	}
+
+	if overallStatus != h.overallStatus {
+		// likewise, the overall status changed by a first result only is not reported
+		if len(events) > 0 {
+			events = append(events, StatusChangeEvent{
+				PreviousStatus: h.overallStatus,
+				Status:         overallStatus,
+				Timestamp:      now,
+			})
+		}
+		h.overallStatus = overallStatus
+	}
+
+	h.scheduleSweepLocked(now)
+
+	return events
+}
+
+// scheduleSweepLocked arms the sweep at the next time a reported health changes without any new result, i.e. a
+// dependency turning stale or results aging out of an error rate window. dependenciesMutex must be held by the caller.
+func (h *healthCheck) scheduleSweepLocked(now time.Time) {
+	var next time.Time
+	for _, d := range h.dependencies {
+		if at, ok := d.nextSweep(now); ok && (next.IsZero() || at.Before(next)) {
+			next = at
+		}
+	}
+
+	if h.sweepTimer != nil {
+		h.sweepTimer.Stop()
+		h.sweepTimer = nil
+	}
+
+	if !next.IsZero() {
+		h.sweepTimer = time.AfterFunc(next.Sub(now), h.sweep)
+	}
+}
+
+// sweep reports the status changes happening without any new result to the watchers and the status change
+// listeners, the next sweep is scheduled by statusEventsLocked.
+func (h *healthCheck) sweep() {
+	h.dependenciesMutex.Lock()
+	events := h.statusEventsLocked()
+	h.dependenciesMutex.Unlock()
+
+	h.notifyWatchers()
+	h.fireStatusChange(events)
+}
+
+// reportedLocked returns the dependency as reported by the endpoints, events and metrics, i.e. unhealthy while
+// blocked by an upstream dependency, with the current error rate, with the stale health treatment, without the panic
+// stack unless debugging and with its override applied. dependenciesMutex must be held by the caller.
+func (h *healthCheck) reportedLocked(d healthDependency) healthDependency {
+	now := time.Now()
+
+	if d.BlockedBy != "" {
+		d.Healthy = false
+	}
+
+	// the errors age out of the window without any new result
+	if d.errorRate != nil {
+		stats := d.errorRate.stats(now)
+		d.ErrorRate = &stats
+		d.Healthy = d.errorRate.healthy(stats)
+	}
+
+	if d.isStale(now) {
+		d.Stale = true
+		d.Healthy = d.staleHealthy
+		d.degraded = false
+	}
+
+	if !h.debug && d.LastError != nil && d.LastError.Stack != "" {
+		lastErr := *d.LastError
+		lastErr.Stack = ""
+		d.LastError = &lastErr
+	}
+
+	return h.applyOverrideLocked(d)
+}
+
+// Refresh checks the dependencies unless the background check worker is running.
+func (h *healthCheck) Refresh(ctx context.Context) {
+	if !h.isBackgroundCheckRunning() {
+		h.runChecksOnDemand(ctx)
+	}
+}
+
+// Healthy returns the overall service health from the last known dependencies health.
+func (h *healthCheck) Healthy() bool {
+	_, resp := h.buildResponse("", "")
+
+	return resp.Healthy
+}
+
+// Dependency returns the reported health of a dependency.
+func (h *healthCheck) Dependency(name string) (DependencyHealth, bool) {
+	h.dependenciesMutex.RLock()
+	defer h.dependenciesMutex.RUnlock()
+
+	d, exist := h.dependencies[name]
+	if !exist {
+		return DependencyHealth{}, false
+	}
+
+	reported := h.reportedLocked(d)
+
+	return reported.health(), true
+}
+
+// Dependencies returns the reported health of all dependencies sorted by name.
+func (h *healthCheck) Dependencies() []DependencyHealth {
+	h.dependenciesMutex.RLock()
+	defer h.dependenciesMutex.RUnlock()
+
+	dependencies := make([]DependencyHealth, 0, len(h.dependencies))
+	for _, d := range h.dependencies {
+		reported := h.reportedLocked(d)
+		dependencies = append(dependencies, reported.health())
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool { return dependencies[i].Name < dependencies[j].Name })
+
+	return dependencies
+}
+
+// ServiceName returns the configured service name.
+func (h *healthCheck) ServiceName() string {
+	return h.serviceName
+}
+
+func (h *healthCheck) fireStatusChange(events []StatusChangeEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	h.listenersMutex.RLock()
+	listeners := h.listeners
+	h.listenersMutex.RUnlock()
+
+	for _, event := range events {
+		for _, listener := range listeners {
+			listener(event)
+		}
+	}
+}
+
 func (h *healthCheck) AddWebservice() []*restful.WebService {
+	return h.newWebservices(defaultHealthCheckPath, "GetHealthcheckInfo", "")
+}
+
+func (h *healthCheck) AddWebserviceV1() []*restfulV1.WebService {
+	return h.newWebservicesV1(defaultHealthCheckPath, "")
+}
+
+// AddLivenessWebservice returns the /livez webservices, which only include dependencies registered with ProbeLiveness.
+func (h *healthCheck) AddLivenessWebservice() []*restful.WebService {
+	return h.newWebservices(defaultLivenessPath, "GetLivenessInfo", ProbeLiveness)
+}
+
+// AddLivenessWebserviceV1 is the go-restful v1 version of AddLivenessWebservice.
+func (h *healthCheck) AddLivenessWebserviceV1() []*restfulV1.WebService {
+	return h.newWebservicesV1(defaultLivenessPath, ProbeLiveness)
+}
+
+// AddReadinessWebservice returns the /readyz webservices, which only include dependencies registered with
+// ProbeReadiness.
+func (h *healthCheck) AddReadinessWebservice() []*restful.WebService {
+	return h.newWebservices(defaultReadinessPath, "GetReadinessInfo", ProbeReadiness)
+}
+
+// AddReadinessWebserviceV1 is the go-restful v1 version of AddReadinessWebservice.
+func (h *healthCheck) AddReadinessWebserviceV1() []*restfulV1.WebService {
+	return h.newWebservicesV1(defaultReadinessPath, ProbeReadiness)
+}
+
+// AddStartupWebservice returns the /startupz webservices, which only include dependencies registered with ProbeStartup.
+func (h *healthCheck) AddStartupWebservice() []*restful.WebService {
+	return h.newWebservices(defaultStartupPath, "GetStartupInfo", ProbeStartup)
+}
+
+// AddStartupWebserviceV1 is the go-restful v1 version of AddStartupWebservice.
+func (h *healthCheck) AddStartupWebserviceV1() []*restfulV1.WebService {
+	return h.newWebservicesV1(defaultStartupPath, ProbeStartup)
+}
+
+// HTTPHandler returns a net/http handler serving the same responses as the go-restful webservices.
+func (h *healthCheck) HTTPHandler() http.Handler {
+	paths := map[string]Probe{
+		defaultHealthCheckPath: "",
+		defaultLivenessPath:    ProbeLiveness,
+		defaultReadinessPath:   ProbeReadiness,
+		defaultStartupPath:     ProbeStartup,
+	}
+
+	mux := http.NewServeMux()
+	for path, probe := range paths {
+		// route to http://example.com/healthz
+		mux.Handle(path, h.handlerHTTP(probe))
+		if h.basePath != "" {
+			// route to http://example.com/basepath/healthz
+			mux.Handle(h.basePath+path, h.handlerHTTP(probe))
+		}
+	}
+
+	// route to http://example.com/healthz/dependencies/{name}
+	prefix := defaultHealthCheckPath + dependencyPath + "/"
+	mux.Handle(prefix, h.dependencyHandlerHTTP(prefix))
+	if h.basePath != "" {
+		// route to http://example.com/basepath/healthz/dependencies/{name}
+		mux.Handle(h.basePath+prefix, h.dependencyHandlerHTTP(h.basePath+prefix))
+	}
+
+	return mux
+}
+
+func (h *healthCheck) newWebservices(path, operation string, probe Probe) []*restful.WebService {
 	webservices := make([]*restful.WebService, 2)
 
 	webservice := new(restful.WebService)
 
-	webservice.Path(defaultHealthCheckPath)
+	webservice.Path(path)
 	// route to http://example.com/healthz
 	webservice.Route(
 		webservice.GET("").
-			To(h.handlerV3).
+			To(h.handlerV3(probe)).
+			Param(webservice.QueryParameter("tag", "only include the dependencies with the tag")).
 			Produces(restful.MIME_JSON).
-			Operation("GetHealthcheckInfo"))
+			Operation(operation))
+	h.addDependencyRoute(webservice, probe, "GetDependencyHealthcheckInfo")
 
 	webservices[0] = webservice
 
@@ -158,28 +678,32 @@ func (h *healthCheck) AddWebservice() []*restful.WebService {
 	}
 
 	webserviceWithBasePath := new(restful.WebService)
-	webserviceWithBasePath.Path(h.basePath + defaultHealthCheckPath)
+	webserviceWithBasePath.Path(h.basePath + path)
 	// route to http://example.com/basepath/healthz
 	webserviceWithBasePath.Route(
 		webserviceWithBasePath.GET("").
-			To(h.handlerV3).
+			To(h.handlerV3(probe)).
+			Param(webserviceWithBasePath.QueryParameter("tag", "only include the dependencies with the tag")).
 			Produces(restful.MIME_JSON).
-			Operation("GetHealthcheckInfoV1"))
+			Operation(operation + "V1"))
+	h.addDependencyRoute(webserviceWithBasePath, probe, "GetDependencyHealthcheckInfoV1")
 
 	webservices[1] = webserviceWithBasePath
 
 	return webservices
 }
 
-func (h *healthCheck) AddWebserviceV1() []*restfulV1.WebService {
+func (h *healthCheck) newWebservicesV1(path string, probe Probe) []*restfulV1.WebService {
 	webservices := make([]*restfulV1.WebService, 2)
 
 	webservice := new(restfulV1.WebService)
-	webservice.Path(defaultHealthCheckPath)
+	webservice.Path(path)
 	// route to http://example.com/healthz
 	webservice.Route(webservice.GET("").
-		To(h.handlerV1).
+		To(h.handlerV1(probe)).
+		Param(webservice.QueryParameter("tag", "only include the dependencies with the tag")).
 		Produces(restful.MIME_JSON))
+	h.addDependencyRouteV1(webservice, probe)
 	webservices[0] = webservice
 
 	if h.basePath == "" {
@@ -187,44 +711,144 @@ func (h *healthCheck) AddWebserviceV1() []*restfulV1.WebService {
 	}
 
 	webserviceWithBasePath := new(restfulV1.WebService)
-	webserviceWithBasePath.Path(h.basePath + defaultHealthCheckPath)
+	webserviceWithBasePath.Path(h.basePath + path)
 	// route to http://example.com/basepath/healthz
 	webserviceWithBasePath.Route(webserviceWithBasePath.GET("").
-		To(h.handlerV1).
+		To(h.handlerV1(probe)).
+		Param(webserviceWithBasePath.QueryParameter("tag", "only include the dependencies with the tag")).
 		Produces(restful.MIME_JSON))
+	h.addDependencyRouteV1(webserviceWithBasePath, probe)
 	webservices[1] = webserviceWithBasePath
 
 	return webservices
 }
 
+// addDependencyRoute adds the single dependency route to the /healthz webservice, the probe webservices only
+// report the whole probe.
+func (h *healthCheck) addDependencyRoute(webservice *restful.WebService, probe Probe, operation string) {
+	if probe != "" {
+		return
+	}
+
+	// route to http://example.com/healthz/dependencies/{name}
+	webservice.Route(
+		webservice.GET(dependencyPath + "/{name}").
+			To(h.dependencyHandlerV3).
+			Param(webservice.PathParameter("name", "dependency name")).
+			Produces(restful.MIME_JSON).
+			Operation(operation))
+}
+
+// addDependencyRouteV1 is the go-restful v1 version of addDependencyRoute.
+func (h *healthCheck) addDependencyRouteV1(webservice *restfulV1.WebService, probe Probe) {
+	if probe != "" {
+		return
+	}
+
+	// route to http://example.com/healthz/dependencies/{name}
+	webservice.Route(webservice.GET(dependencyPath + "/{name}").
+		To(h.dependencyHandlerV1).
+		Param(webservice.PathParameter("name", "dependency name")).
+		Produces(restful.MIME_JSON))
+}
+
 func (h *healthCheck) StartBackgroundCheck(ctx context.Context) {
-	if h.bgCheckRunning {
+	if !atomic.CompareAndSwapInt32(&h.bgCheckRunning, 0, 1) {
 		return
 	}
-	h.bgCheckRunning = true
-	h.runChecks()
-
-	ticker := time.NewTicker(h.bgCheckInterval)
-
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				h.runChecks()
-			case <-ctx.Done():
-				ticker.Stop()
-				h.bgCheckRunning = false
-				logrus.Info("Background health check worker stopped")
-				return
+	h.runChecks(ctx)
+
+	nextChecks := make(map[string]time.Time)
+	for name, d := range h.copyDependencies() {
+		nextChecks[name] = h.nextCheck(d)
+	}
+
+	go h.schedule(ctx, nextChecks)
+}
+
+// schedule runs every dependency check on its own interval until the context is done. A dependency without
+// next check time, i.e. added after the background check started, is checked immediately.
+func (h *healthCheck) schedule(ctx context.Context, nextChecks map[string]time.Time) {
+	done := make(chan healthDependency)
+	running := make(map[string]bool)
+
+	for {
+		now := time.Now()
+		dependencies := h.copyDependencies()
+		wait := h.bgCheckInterval
+		var due []healthDependency
+
+		for name := range nextChecks {
+			if _, exist := dependencies[name]; !exist {
+				delete(nextChecks, name)
 			}
 		}
-	}()
+
+		for name, d := range dependencies {
+			if running[name] {
+				continue
+			}
+
+			if next, exist := nextChecks[name]; exist && next.After(now) {
+				if next.Sub(now) < wait {
+					wait = next.Sub(now)
+				}
+
+				continue
+			}
+
+			running[name] = true
+			due = append(due, d)
+		}
+
+		if len(due) > 0 {
+			go h.checkDependencies(ctx, due, func(d healthDependency) {
+				select {
+				case done <- d:
+				case <-ctx.Done():
+				}
+			})
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case d := <-done:
+			delete(running, d.Name)
+			nextChecks[d.Name] = h.nextCheck(d)
+			h.notifyWatchers()
+		case <-timer.C:
+		case <-h.scheduleCh:
+		case <-ctx.Done():
+			timer.Stop()
+			atomic.StoreInt32(&h.bgCheckRunning, 0)
+			h.logger.Info("Background health check worker stopped")
+			return
+		}
+		timer.Stop()
+	}
 }
 
-// nolint: gomnd
-func (h *healthCheck) runChecks() {
-	wg := &sync.WaitGroup{}
+func (h *healthCheck) isBackgroundCheckRunning() bool {
+	return atomic.LoadInt32(&h.bgCheckRunning) == 1
+}
+
+// nextCheck returns the next check time of the dependency, delayed by a random jitter if configured.
+func (h *healthCheck) nextCheck(d healthDependency) time.Time {
+	interval := d.interval
+	if interval <= 0 {
+		// never re-run a check right after it finished
+		interval = h.bgCheckInterval
+	}
 
+	next := time.Now().Add(interval)
+	if h.bgCheckJitter > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(h.bgCheckJitter)))) // nolint: gosec
+	}
+
+	return next
+}
+
+func (h *healthCheck) copyDependencies() map[string]healthDependency {
 	//making a new copy of healthDependencies to avoid race condition
 	healthDependencies := make(map[string]healthDependency)
 	h.dependenciesMutex.Lock()
@@ -233,68 +857,462 @@ func (h *healthCheck) runChecks() {
 	}
 	h.dependenciesMutex.Unlock()
 
-	for _, d := range healthDependencies {
+	return healthDependencies
+}
+
+func (h *healthCheck) runChecks(ctx context.Context) {
+	dependencies := make([]healthDependency, 0)
+	for _, d := range h.copyDependencies() {
+		dependencies = append(dependencies, d)
+	}
+
+	h.checkDependencies(ctx, dependencies, nil)
+	h.notifyWatchers()
+}
+
+// checkDependencies runs the dependency checks concurrently as a single observed run. A dependency is checked after
+// its upstream dependencies of the same run. done, if any, is called as soon as each check finishes.
+func (h *healthCheck) checkDependencies(ctx context.Context, dependencies []healthDependency,
+	done func(d healthDependency)) {
+	ctx, end := h.observer.StartRun(ctx)
+	defer end()
+
+	wg := &sync.WaitGroup{}
+	finished := make(map[string]chan struct{}, len(dependencies))
+	for _, d := range dependencies {
+		finished[d.Name] = make(chan struct{})
+	}
+
+	for _, d := range dependencies {
 		wg.Add(1)
-		go h.check(wg, d)
+		go func(d healthDependency) {
+			defer wg.Done()
+			defer close(finished[d.Name])
+
+			for _, upstream := range d.DependsOn {
+				if ch, exist := finished[upstream]; exist {
+					select {
+					case <-ch:
+					case <-ctx.Done():
+					}
+				}
+			}
+
+			h.check(ctx, d)
+			if done != nil {
+				done(d)
+			}
+		}(d)
 	}
 
 	wg.Wait()
 }
 
-func (h *healthCheck) check(wg *sync.WaitGroup, d healthDependency) {
-	defer wg.Done()
-	d.check()
+// Watch registers a channel that receives a signal every time dependencies health might have been changed.
+// The returned function must be called to unregister the channel.
+func (h *healthCheck) Watch() (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.watchersMutex.Lock()
+	h.watchers[ch] = struct{}{}
+	h.watchersMutex.Unlock()
+
+	return ch, func() {
+		h.watchersMutex.Lock()
+		delete(h.watchers, ch)
+		h.watchersMutex.Unlock()
+	}
+}
+
+func (h *healthCheck) notifyWatchers() {
+	h.watchersMutex.Lock()
+	defer h.watchersMutex.Unlock()
+
+	for ch := range h.watchers {
+		// skip if there is already a pending signal, the watcher will read the latest health anyway
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (h *healthCheck) check(ctx context.Context, d healthDependency) {
+	if d.checkFunc == nil {
+		// the health is updated using UpdateHealth, do not overwrite it with the copy
+		return
+	}
+
 	h.dependenciesMutex.Lock()
-	defer h.dependenciesMutex.Unlock()
-	h.dependencies[d.Name] = d
+	if upstream := h.blockingUpstreamLocked(d); upstream != "" {
+		// skip the check and keep the last result, the upstream dependency is reported instead
+		previous := h.dependencies[d.Name]
+		d = previous
+		d.BlockedBy = upstream
+		events := h.storeDependencyLocked(d)
+		h.dependenciesMutex.Unlock()
+
+		h.logStatusChange(previous, d, nil)
+		h.fireStatusChange(events)
+
+		return
+	}
+	started, inFlight := h.inFlight[d.Name]
+	if !inFlight {
+		h.inFlight[d.Name] = time.Now()
+	}
+	h.dependenciesMutex.Unlock()
+
+	if inFlight && (d.timeout <= 0 || time.Since(started) < d.timeout) {
+		// a concurrent check run is in progress and will store its result
+		return
+	}
+
+	ctx, end := h.observer.StartCheck(ctx, CheckInfo{Name: d.Name, URL: d.URL, HardDependency: d.HardDependency})
+	now := time.Now()
+	var err error
+	var elapsed time.Duration
+	if inFlight {
+		// the previous check function is still hung after timing out, report the timeout again instead of
+		// piling up another call to the dependency
+		err = fmt.Errorf("timed out after %s", d.timeout)
+		elapsed = time.Since(started)
+	} else {
+		err = d.runCheckFunc(ctx, func() {
+			h.dependenciesMutex.Lock()
+			delete(h.inFlight, d.Name)
+			h.dependenciesMutex.Unlock()
+		})
+		elapsed = time.Since(now)
+	}
+	if ctx.Err() != nil {
+		// the check run was abandoned, keep the last known result
+		end(err)
+
+		return
+	}
+
+	// record the result onto the current dependency state rather than the copy taken before the run, so that
+	// concurrent check runs do not overwrite each other's counters
+	h.dependenciesMutex.Lock()
+	previous := h.dependencies[d.Name]
+	d = previous
+	err = d.record(now, elapsed, err)
+	events := h.storeDependencyLocked(d)
+	h.dependenciesMutex.Unlock()
+	end(err)
+
+	h.logStatusChange(previous, d, err)
+	h.fireStatusChange(events)
 }
 
-func (h *healthCheck) getResponse() (int, *response) {
-	otherComponents := make([]healthOtherComponent, 0)
-	healthStatusResp := &response{
-		Name:    h.serviceName,
-		Healthy: true,
-		Others:  otherComponents,
+// logStatusChange logs a check failure or recovery only when the dependency status changes, so that a dependency
+// which stays down does not log on every check run.
+func (h *healthCheck) logStatusChange(previous, d healthDependency, err error) {
+	var previousStatus Status
+	if previous.LastCall != nil {
+		previousStatus = previous.status()
 	}
 
+	status := d.status()
+	switch {
+	case status == previousStatus:
+	case status == StatusSkipped:
+		h.logger.Warn("Dependency health check skipped", "dependency", d.Name, "blockedBy", d.BlockedBy)
+	case status == StatusPass:
+		if previousStatus != "" {
+			h.logger.Info("Dependency health check recovered", "dependency", d.Name)
+		}
+	case d.degraded:
+		h.logger.Warn("Dependency is degraded", "dependency", d.Name, "error", err)
+	case err == nil:
+		// the check succeeded, the dependency turns healthy once the success threshold is reached
+		h.logger.Info("Dependency health check waiting for the success threshold", "dependency", d.Name,
+			"consecutiveSuccesses", d.ConsecutiveSuccesses, "successThreshold", d.successThreshold)
+	default:
+		keysAndValues := []interface{}{"dependency", d.Name, "error", err, "consecutiveFailures", d.ConsecutiveFailures}
+		var panicErr *panicError
+		if errors.As(err, &panicErr) {
+			keysAndValues = append(keysAndValues, "stack", string(panicErr.stack))
+		}
+		h.logger.Error("Dependency health check failed", keysAndValues...)
+	}
+}
+
+// getResponse builds the health response of the dependencies counting toward the probe and tagged with the tag.
+// An empty probe includes every dependency, likewise an empty tag.
+func (h *healthCheck) getResponse(ctx context.Context, probe Probe, tag string) (int, *response) {
 	// if background health check worker is not running, check immediately
-	if !h.bgCheckRunning {
-		h.runChecks()
+	if !h.isBackgroundCheckRunning() {
+		age := h.runChecksOnDemand(ctx)
+		responseStatus, healthStatusResp := h.buildResponse(probe, tag)
+		if h.minCheckInterval > 0 {
+			cacheAge := duration(age)
+			healthStatusResp.CacheAge = &cacheAge
+		}
+
+		return responseStatus, healthStatusResp
+	}
+
+	return h.buildResponse(probe, tag)
+}
+
+// runChecksOnDemand runs the checks of a health request when the background check worker is not running.
+// Concurrent requests share a single check run and the results of a run within the minimum check interval are
+// reused. It returns the age of the results.
+func (h *healthCheck) runChecksOnDemand(ctx context.Context) time.Duration {
+	if age, ok := h.resultsAge(); ok && age < h.minCheckInterval {
+		return age
+	}
+
+	// the run is shared with the concurrent requests, hence it is not canceled along with the request which started
+	// it, the checks are bounded by their own timeout
+	runCtx := detachedContext{parent: ctx}
+	run := h.runGroup.DoChan("runChecks", func() (interface{}, error) {
+		h.runChecks(runCtx)
+		atomic.StoreInt64(&h.lastRun, time.Now().UnixNano())
+
+		return nil, nil
+	})
+
+	select {
+	case <-run:
+	case <-ctx.Done():
+		// the request is gone, the run goes on for the other requests sharing it
 	}
 
+	age, _ := h.resultsAge()
+
+	return age
+}
+
+// detachedContext keeps the values of its parent context, e.g. the tracing span, without its deadline and
+// cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+// resultsAge returns the time elapsed since the last completed on-demand check run, if any.
+func (h *healthCheck) resultsAge() (time.Duration, bool) {
+	lastRun := atomic.LoadInt64(&h.lastRun)
+	if lastRun == 0 {
+		return 0, false
+	}
+
+	return time.Since(time.Unix(0, lastRun)), true
+}
+
+// getDependencyResponse builds the health response of a single dependency. The status code only depends on that
+// dependency, 503 when it is unhealthy. If the background health check worker is not running, only that dependency
+// is checked.
+func (h *healthCheck) getDependencyResponse(ctx context.Context, name string) (int, *healthDependency, error) {
+	h.dependenciesMutex.RLock()
+	d, exist := h.dependencies[name]
+	h.dependenciesMutex.RUnlock()
+	if !exist {
+		return http.StatusNotFound, nil, errDependencyNotFound
+	}
+
+	if !h.isBackgroundCheckRunning() {
+		h.checkDependencies(ctx, []healthDependency{d}, nil)
+		h.notifyWatchers()
+	}
+
+	h.dependenciesMutex.RLock()
+	d = h.reportedLocked(h.dependencies[name])
+	h.dependenciesMutex.RUnlock()
+	d.Status = d.status()
+
+	if !d.Healthy {
+		return http.StatusServiceUnavailable, &d, nil
+	}
+
+	return http.StatusOK, &d, nil
+}
+
+// buildResponse builds the health response from the last known dependencies health without running the checks.
+func (h *healthCheck) buildResponse(probe Probe, tag string) (int, *response) {
+	healthStatusResp := &response{
+		Name:    h.serviceName,
+		Healthy: true,
+		Status:  StatusPass,
+		Groups:  make([]healthGroup, 0),
+	}
+
+	included := make(map[string]bool)
 	h.dependenciesMutex.Lock()
 	for _, v := range h.dependencies {
-		healthStatusResp.appendHealthCheckDependency(v)
+		if v.hasProbe(probe) && v.hasTag(tag) {
+			v = h.reportedLocked(v)
+			v.Status = v.status()
+			healthStatusResp.appendHealthCheckDependency(v)
+			included[v.Name] = true
+		}
+	}
+
+	// a rule is reported along with any of its dependencies, evaluated with all of them
+	for _, rule := range h.rules {
+		for _, name := range rule.dependencies {
+			if included[name] {
+				healthStatusResp.Rules = append(healthStatusResp.Rules, h.evaluateRuleLocked(rule))
+
+				break
+			}
+		}
 	}
 	h.dependenciesMutex.Unlock()
 
+	healthStatusResp.computeGroups()
+
 	responseStatus := http.StatusOK
 
 	for _, dependency := range healthStatusResp.Dependencies {
-		if !dependency.Healthy && dependency.HardDependency {
-			responseStatus = http.StatusServiceUnavailable
-			healthStatusResp.Healthy = false
-			break
+		if impact := dependency.impact(); impact.severity() > healthStatusResp.Status.severity() {
+			healthStatusResp.Status = impact
+		}
+	}
+
+	for _, rule := range healthStatusResp.Rules {
+		if rule.Status.severity() > healthStatusResp.Status.severity() {
+			healthStatusResp.Status = rule.Status
 		}
 	}
 
+	// the liveness and startup probes are not affected, the service is still alive while draining
+	if h.isDraining() && (probe == "" || probe == ProbeReadiness) {
+		healthStatusResp.Draining = true
+		healthStatusResp.Status = StatusFail
+	}
+
+	if healthStatusResp.Status == StatusFail {
+		responseStatus = http.StatusServiceUnavailable
+		healthStatusResp.Healthy = false
+	}
+
 	return responseStatus, healthStatusResp
 }
 
 // handlerV3 will support for go-restful v3
-func (h *healthCheck) handlerV3(_ *restful.Request, resp *restful.Response) {
-	responseStatus, healthStatus := h.getResponse()
+func (h *healthCheck) handlerV3(probe Probe) restful.RouteFunction {
+	return func(req *restful.Request, resp *restful.Response) {
+		responseStatus, healthStatus := h.getResponse(req.Request.Context(), probe, req.QueryParameter("tag"))
 
-	if err := resp.WriteHeaderAndJson(responseStatus, healthStatus, restful.MIME_JSON); err != nil {
-		logrus.Error("Error " + err.Error())
+		if err := resp.WriteHeaderAndJson(responseStatus, healthStatus, restful.MIME_JSON); err != nil {
+			h.logger.Error("Unable to write health response", "error", err)
+		}
 	}
 }
 
 // handlerV1 will support for go-restful v1
-func (h *healthCheck) handlerV1(_ *restfulV1.Request, resp *restfulV1.Response) {
-	responseStatus, healthStatus := h.getResponse()
+func (h *healthCheck) handlerV1(probe Probe) restfulV1.RouteFunction {
+	return func(req *restfulV1.Request, resp *restfulV1.Response) {
+		responseStatus, healthStatus := h.getResponse(req.Request.Context(), probe, req.QueryParameter("tag"))
+
+		if err := resp.WriteHeaderAndJson(responseStatus, healthStatus, restful.MIME_JSON); err != nil {
+			h.logger.Error("Unable to write health response", "error", err)
+		}
+	}
+}
+
+// handlerHTTP will support for net/http
+func (h *healthCheck) handlerHTTP(probe Probe) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		responseStatus, healthStatus := h.getResponse(r.Context(), probe, r.URL.Query().Get("tag"))
+
+		body, err := json.MarshalIndent(healthStatus, "", " ")
+		if err != nil {
+			h.logger.Error("Unable to write health response", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
 
-	if err := resp.WriteHeaderAndJson(responseStatus, healthStatus, restful.MIME_JSON); err != nil {
-		logrus.Error("Error " + err.Error())
+		w.Header().Set("Content-Type", restful.MIME_JSON)
+		w.WriteHeader(responseStatus)
+		if _, err = w.Write(body); err != nil {
+			h.logger.Error("Unable to write health response", "error", err)
+		}
+	}
+}
+
+// dependencyHandlerV3 serves a single dependency for go-restful v3
+func (h *healthCheck) dependencyHandlerV3(req *restful.Request, resp *restful.Response) {
+	responseStatus, dependency, err := h.getDependencyResponse(req.Request.Context(), req.PathParameter("name"))
+	if err != nil {
+		err = resp.WriteErrorString(responseStatus, err.Error())
+	} else {
+		err = resp.WriteHeaderAndJson(responseStatus, dependency, restful.MIME_JSON)
+	}
+	if err != nil {
+		h.logger.Error("Unable to write health response", "error", err)
+	}
+}
+
+// dependencyHandlerV1 serves a single dependency for go-restful v1
+func (h *healthCheck) dependencyHandlerV1(req *restfulV1.Request, resp *restfulV1.Response) {
+	responseStatus, dependency, err := h.getDependencyResponse(req.Request.Context(), req.PathParameter("name"))
+	if err != nil {
+		err = resp.WriteErrorString(responseStatus, err.Error())
+	} else {
+		err = resp.WriteHeaderAndJson(responseStatus, dependency, restful.MIME_JSON)
+	}
+	if err != nil {
+		h.logger.Error("Unable to write health response", "error", err)
+	}
+}
+
+// dependencyHandlerHTTP serves a single dependency for net/http, the dependency name follows the prefix
+func (h *healthCheck) dependencyHandlerHTTP(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		responseStatus, dependency, err := h.getDependencyResponse(r.Context(), strings.TrimPrefix(r.URL.Path, prefix))
+		if err != nil {
+			http.Error(w, err.Error(), responseStatus)
+
+			return
+		}
+
+		body, err := json.MarshalIndent(dependency, "", " ")
+		if err != nil {
+			h.logger.Error("Unable to write health response", "error", err)
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", restful.MIME_JSON)
+		w.WriteHeader(responseStatus)
+		if _, err = w.Write(body); err != nil {
+			h.logger.Error("Unable to write health response", "error", err)
+		}
 	}
 }
@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -332,3 +336,681 @@ func Test_AddHealthCheck(t *testing.T) {
 		})
 	}
 }
+
+func Test_ProbeEndpoints(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, BasePath: servicePath})
+
+	container := restful.NewContainer()
+	for _, webService := range h.AddWebservice() {
+		container.Add(webService)
+	}
+	for _, webService := range h.AddLivenessWebservice() {
+		container.Add(webService)
+	}
+	for _, webService := range h.AddReadinessWebservice() {
+		container.Add(webService)
+	}
+	for _, webService := range h.AddStartupWebservice() {
+		container.Add(webService)
+	}
+
+	h.AddHardHealthCheck("redis", testURL, func() error { return fmt.Errorf("error") })
+	h.AddHardHealthCheck("config", testURL, func() error { return nil }, WithProbes(ProbeLiveness, ProbeStartup))
+
+	tests := []struct {
+		path             string
+		wantCode         int
+		wantDependencies int
+	}{
+		{path: "/healthz", wantCode: http.StatusServiceUnavailable, wantDependencies: 2},
+		{path: "/livez", wantCode: http.StatusOK, wantDependencies: 1},
+		{path: "/readyz", wantCode: http.StatusServiceUnavailable, wantDependencies: 1},
+		{path: "/startupz", wantCode: http.StatusOK, wantDependencies: 1},
+		{path: servicePath + "/readyz", wantCode: http.StatusServiceUnavailable, wantDependencies: 1},
+	}
+
+	for _, tt := range tests {
+		resp, _, err :=
+			caller.Call(container).
+				To(gorequest.New().
+					Get(tt.path).
+					MakeRequest()).
+				Read(&response{}).
+				Execute()
+		require.NoError(t, err)
+		assert.Equal(t, tt.wantCode, resp.Code, tt.path)
+
+		var body response
+		_ = json.Unmarshal(resp.Body.Bytes(), &body)
+		assert.Equal(t, tt.wantDependencies, len(body.Dependencies), tt.path)
+	}
+}
+
+func Test_HTTPHandler(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, BasePath: servicePath})
+	h.AddHardHealthCheck("test", testURL, func() error { return fmt.Errorf("error") })
+
+	server := httptest.NewServer(h.HTTPHandler())
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{path: "/healthz", wantCode: http.StatusServiceUnavailable},
+		{path: servicePath + "/healthz", wantCode: http.StatusServiceUnavailable},
+		{path: "/livez", wantCode: http.StatusOK},
+		{path: servicePath + "/readyz", wantCode: http.StatusServiceUnavailable},
+		{path: "/unknown", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		resp, err := http.Get(server.URL + tt.path)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, tt.wantCode, resp.StatusCode, tt.path)
+		if tt.wantCode != http.StatusNotFound {
+			assert.Equal(t, restful.MIME_JSON, resp.Header.Get("Content-Type"), tt.path)
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/healthz")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.False(t, body.Healthy)
+	assert.Equal(t, serviceName, body.Name)
+	assert.Len(t, body.Dependencies, 1)
+}
+
+func Test_DependencyEndpoint(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, BasePath: servicePath})
+
+	var redisChecks, elasticChecks int32
+	h.AddHardHealthCheck("redis", testURL, func() error {
+		atomic.AddInt32(&redisChecks, 1)
+		return nil
+	})
+	h.AddHealthCheck("elastic", testURL, func() error {
+		atomic.AddInt32(&elasticChecks, 1)
+		return fmt.Errorf("error")
+	})
+
+	container := restful.NewContainer()
+	for _, webService := range h.AddWebservice() {
+		container.Add(webService)
+	}
+	containerV1 := restfulV1.NewContainer()
+	for _, webService := range h.AddWebserviceV1() {
+		containerV1.Add(webService)
+	}
+	server := httptest.NewServer(h.HTTPHandler())
+	defer server.Close()
+
+	tests := []struct {
+		path     string
+		wantCode int
+	}{
+		{path: "/healthz/dependencies/redis", wantCode: http.StatusOK},
+		// a soft dependency only affects its own status code
+		{path: servicePath + "/healthz/dependencies/elastic", wantCode: http.StatusServiceUnavailable},
+		{path: "/healthz/dependencies/unknown", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		for _, handler := range []http.Handler{container, containerV1, server.Config.Handler} {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, recorder.Code, tt.path)
+			if tt.wantCode == http.StatusNotFound {
+				continue
+			}
+
+			var body healthDependency
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body), tt.path)
+			assert.Equal(t, tt.wantCode == http.StatusOK, body.Healthy, tt.path)
+			assert.NotNil(t, body.LastCall, tt.path)
+		}
+	}
+
+	// only the requested dependency is checked
+	assert.Equal(t, int32(3), atomic.LoadInt32(&redisChecks))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&elasticChecks))
+}
+
+func Test_Tags(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("postgres", testURL, func() error { return fmt.Errorf("error") }, WithTags("storage"))
+	h.AddHealthCheck("redis", testURL, func() error { return nil }, WithTags("storage", "cache"))
+	h.AddHardHealthCheck("kafka", testURL, func() error { return nil }, WithTags("messaging"))
+
+	server := httptest.NewServer(h.HTTPHandler())
+	defer server.Close()
+
+	get := func(path string) (int, *response) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body := &response{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(body))
+
+		return resp.StatusCode, body
+	}
+
+	code, body := get("/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Len(t, body.Dependencies, 3)
+	assert.Equal(t, []healthGroup{
+		{Name: "cache", Healthy: true, Status: StatusPass, Dependencies: []string{"redis"}},
+		{Name: "messaging", Healthy: true, Status: StatusPass, Dependencies: []string{"kafka"}},
+		{Name: "storage", Healthy: false, Status: StatusFail, Dependencies: []string{"postgres", "redis"}},
+	}, body.Groups)
+
+	code, body = get("/healthz?tag=messaging")
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, body.Dependencies, 1)
+	assert.Equal(t, "kafka", body.Dependencies[0].Name)
+	assert.Equal(t, StatusPass, body.Status)
+
+	code, body = get("/healthz?tag=storage")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Len(t, body.Dependencies, 2)
+
+	code, body = get("/healthz?tag=unknown")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, body.Dependencies)
+	assert.Empty(t, body.Groups)
+}
+
+func Test_CheckTimeout(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, CheckTimeout: time.Second})
+
+	block := make(chan struct{})
+	defer close(block)
+
+	h.AddHardHealthCheck("hung", testURL, func() error {
+		<-block
+		return nil
+	}, WithTimeout(100*time.Millisecond))
+	h.AddHardHealthCheck("healthy", testURL, func() error { return nil })
+
+	start := time.Now()
+	code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	for _, dependency := range resp.Dependencies {
+		if dependency.Name == "hung" {
+			assert.False(t, dependency.Healthy)
+			require.NotNil(t, dependency.LastError)
+			assert.Equal(t, "timed out after 100ms", dependency.LastError.Message)
+		} else {
+			assert.True(t, dependency.Healthy)
+		}
+	}
+}
+
+func Test_CheckTimeoutSingleInFlight(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+
+	block := make(chan struct{})
+	defer close(block)
+
+	var calls, inFlight, maxInFlight int32
+	h.AddHardHealthCheck("hung", testURL, func() error {
+		atomic.AddInt32(&calls, 1)
+		if n := atomic.AddInt32(&inFlight, 1); n > atomic.LoadInt32(&maxInFlight) {
+			atomic.StoreInt32(&maxInFlight, n)
+		}
+		defer atomic.AddInt32(&inFlight, -1)
+		<-block
+		return nil
+	}, WithTimeout(50*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		// the first call times out, the next calls report the timeout of the still hung call
+		time.Sleep(60 * time.Millisecond)
+		code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		require.Len(t, resp.Dependencies, 1)
+		require.NotNil(t, resp.Dependencies[0].LastError)
+		assert.Equal(t, "timed out after 50ms", resp.Dependencies[0].LastError.Message)
+		assert.Equal(t, i+1, resp.Dependencies[0].ConsecutiveFailures)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxInFlight))
+}
+
+func Test_CheckPanic(t *testing.T) {
+	for _, debug := range []bool{false, true} {
+		h := New(&Config{ServiceName: serviceName, Debug: debug})
+		h.AddHardHealthCheck("panic", testURL, func() error { panic("boom") })
+		h.AddHardHealthCheck("nil", testURL, func() error {
+			var client *http.Client
+			_, err := client.Get(testURL)
+			return err
+		}, WithTimeout(0))
+		h.AddHealthCheck("test", testURL, func() error { return nil })
+
+		code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+
+		dependencies := make(map[string]healthDependency)
+		for _, d := range resp.Dependencies {
+			dependencies[d.Name] = d
+		}
+		assert.True(t, dependencies["test"].Healthy)
+
+		for _, name := range []string{"panic", "nil"} {
+			d := dependencies[name]
+			assert.False(t, d.Healthy, name)
+			require.NotNil(t, d.LastError, name)
+			assert.True(t, strings.HasPrefix(d.LastError.Message, "panic: "), d.LastError.Message)
+			if debug {
+				assert.Contains(t, d.LastError.Stack, "Test_CheckPanic", name)
+			} else {
+				assert.Empty(t, d.LastError.Stack, name)
+			}
+		}
+		assert.Equal(t, "panic: boom", dependencies["panic"].LastError.Message)
+	}
+}
+
+func Test_OnDemandCoalescing(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+
+	var checks int32
+	h.AddHardHealthCheck("test", testURL, func() error {
+		atomic.AddInt32(&checks, 1)
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	})
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+			assert.Equal(t, http.StatusOK, code)
+			assert.Nil(t, resp.CacheAge)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&checks))
+}
+
+func Test_OnDemandCanceledRequest(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+
+	var checks int32
+	h.AddHardHealthCheckWithContext("test", testURL, func(ctx context.Context) error {
+		atomic.AddInt32(&checks, 1)
+		select {
+		case <-time.After(100 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	// the first request gives up before the check ends
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	first := make(chan struct{})
+	go func() {
+		defer close(first)
+		h.(*healthCheck).getResponse(ctx, "", "")
+	}()
+
+	// the second request joins the run started by the first one, which must not be canceled along with it
+	time.Sleep(10 * time.Millisecond)
+	code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+	<-first
+
+	assert.Equal(t, http.StatusOK, code)
+	require.Len(t, resp.Dependencies, 1)
+	assert.True(t, resp.Dependencies[0].Healthy)
+	assert.NotNil(t, resp.Dependencies[0].LastCall)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&checks))
+}
+
+func Test_MinCheckInterval(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, MinCheckInterval: 200 * time.Millisecond})
+
+	var checks int32
+	h.AddHardHealthCheck("test", testURL, func() error {
+		atomic.AddInt32(&checks, 1)
+		return nil
+	})
+
+	_, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+	require.NotNil(t, resp.CacheAge)
+	assert.Less(t, time.Duration(*resp.CacheAge), 50*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	_, resp = h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&checks))
+	require.NotNil(t, resp.CacheAge)
+	assert.GreaterOrEqual(t, time.Duration(*resp.CacheAge), 100*time.Millisecond)
+
+	time.Sleep(150 * time.Millisecond)
+	_, resp = h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&checks))
+	assert.Less(t, time.Duration(*resp.CacheAge), 50*time.Millisecond)
+}
+
+func Test_AddHealthCheckWithContext(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, CheckTimeout: 100 * time.Millisecond})
+
+	h.AddHardHealthCheckWithContext("ctx", testURL, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	h.AddHealthCheckWithContext("nil", testURL, nil)
+
+	code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	require.Len(t, resp.Dependencies, 2)
+
+	// a canceled health request keeps the last known result
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, h.UpdateHealth("ctx", true, nil))
+	code, _ = h.(*healthCheck).getResponse(ctx, "", "")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func Test_ConcurrentChecks(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName}).(*healthCheck)
+	h.AddHardHealthCheck("flaky", testURL, func() error { return fmt.Errorf("error") },
+		WithFailureThreshold(3))
+
+	// concurrent runs take their copies before any of them stores its result
+	stale := h.copyDependencies()["flaky"]
+	h.check(context.Background(), stale)
+	h.check(context.Background(), stale)
+	h.check(context.Background(), stale)
+
+	dependency := h.copyDependencies()["flaky"]
+	assert.Equal(t, uint64(3), dependency.checks)
+	assert.Equal(t, uint64(3), dependency.checkFailures)
+	assert.Equal(t, 3, dependency.ConsecutiveFailures)
+	assert.False(t, dependency.Healthy)
+}
+
+func Test_Thresholds(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+
+	results := []error{nil, nil, fmt.Errorf("error"), fmt.Errorf("error"), nil, nil}
+	var i int
+	h.AddHardHealthCheck("redis", testURL, func() error {
+		err := results[i]
+		i++
+		return err
+	}, WithFailureThreshold(2), WithSuccessThreshold(2))
+
+	wantHealthy := []bool{false, true, true, false, false, true}
+	wantFailures := []int{0, 0, 1, 2, 0, 0}
+	for step := range results {
+		_, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+		require.Len(t, resp.Dependencies, 1)
+		assert.Equal(t, wantHealthy[step], resp.Dependencies[0].Healthy, "step %d", step)
+		assert.Equal(t, wantFailures[step], resp.Dependencies[0].ConsecutiveFailures, "step %d", step)
+	}
+}
+
+func Test_Status(t *testing.T) {
+	tests := []struct {
+		name       string
+		hard       bool
+		err        error
+		wantStatus Status
+		wantCode   int
+	}{
+		{name: "healthy hard dependency", hard: true, err: nil, wantStatus: StatusPass, wantCode: http.StatusOK},
+		{name: "unhealthy soft dependency", hard: false, err: fmt.Errorf("error"), wantStatus: StatusWarn,
+			wantCode: http.StatusOK},
+		{name: "unhealthy hard dependency", hard: true, err: fmt.Errorf("error"), wantStatus: StatusFail,
+			wantCode: http.StatusServiceUnavailable},
+		{name: "degraded hard dependency", hard: true, err: NewDegradedError(fmt.Errorf("lag")),
+			wantStatus: StatusWarn, wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(&Config{ServiceName: serviceName})
+			if tt.hard {
+				h.AddHardHealthCheck("test", testURL, func() error { return tt.err })
+			} else {
+				h.AddHealthCheck("test", testURL, func() error { return tt.err })
+			}
+			h.AddHardHealthCheck("other", testURL, func() error { return nil })
+
+			code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+			assert.Equal(t, tt.wantCode, code)
+			assert.Equal(t, tt.wantStatus, resp.Status)
+
+			for _, dependency := range resp.Dependencies {
+				if dependency.Name == "test" {
+					assert.Equal(t, tt.wantStatus, dependency.Status)
+				} else {
+					assert.Equal(t, StatusPass, dependency.Status)
+				}
+			}
+		})
+	}
+}
+
+func Test_BackgroundCheckInterval(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, BackgroundCheckInterval: time.Hour,
+		BackgroundCheckJitter: 10 * time.Millisecond})
+
+	var fastCount, slowCount, lateCount int32
+	h.AddHealthCheck("fast", testURL, func() error {
+		atomic.AddInt32(&fastCount, 1)
+		return nil
+	}, WithInterval(50*time.Millisecond))
+	h.AddHealthCheck("slow", testURL, func() error {
+		atomic.AddInt32(&slowCount, 1)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.StartBackgroundCheck(ctx)
+
+	h.AddHealthCheck("late", testURL, func() error {
+		atomic.AddInt32(&lateCount, 1)
+		return nil
+	})
+
+	time.Sleep(500 * time.Millisecond)
+
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&fastCount), int32(4))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&slowCount))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&lateCount))
+}
+
+func Test_BackgroundCheckZeroInterval(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName, BackgroundCheckInterval: time.Hour})
+
+	var count int32
+	h.AddHealthCheck("zero", testURL, func() error {
+		atomic.AddInt32(&count, 1)
+		return nil
+	}, WithInterval(0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h.StartBackgroundCheck(ctx)
+
+	time.Sleep(200 * time.Millisecond)
+
+	// falls back to the background check interval instead of re-running the check right away
+	assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+}
+
+func Test_StaleAfter(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("email", testURL, nil, WithStaleAfter(100*time.Millisecond, false))
+	h.AddHardHealthCheck("sms", testURL, nil, WithStaleAfter(100*time.Millisecond, true))
+	h.AddHardHealthCheck("push", testURL, nil)
+
+	for _, name := range []string{"email", "sms", "push"} {
+		require.NoError(t, h.UpdateHealth(name, true, nil))
+	}
+
+	events := make(chan StatusChangeEvent, 10)
+	h.OnStatusChange(func(evt StatusChangeEvent) {
+		events <- evt
+	})
+	updated, unwatch := h.Watch()
+	defer unwatch()
+
+	code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusOK, code)
+	for _, d := range resp.Dependencies {
+		assert.False(t, d.Stale, d.Name)
+	}
+
+	// turning stale is reported without any new result
+	select {
+	case evt := <-events:
+		assert.Equal(t, "email", evt.Dependency)
+		assert.Equal(t, StatusPass, evt.PreviousStatus)
+		assert.Equal(t, StatusFail, evt.Status)
+		assert.Equal(t, "not updated within 100ms", evt.Error)
+	case <-time.After(time.Second):
+		require.Fail(t, "stale status change is not reported")
+	}
+	evt := <-events
+	assert.Equal(t, "", evt.Dependency)
+	assert.Equal(t, StatusFail, evt.Status)
+	select {
+	case <-updated:
+	default:
+		assert.Fail(t, "watchers are not notified")
+	}
+
+	code, resp = h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	dependencies := make(map[string]healthDependency)
+	for _, d := range resp.Dependencies {
+		dependencies[d.Name] = d
+	}
+	assert.True(t, dependencies["email"].Stale)
+	assert.False(t, dependencies["email"].Healthy)
+	assert.Equal(t, StatusFail, dependencies["email"].Status)
+	assert.True(t, dependencies["sms"].Stale)
+	assert.True(t, dependencies["sms"].Healthy)
+	assert.Equal(t, StatusPass, dependencies["sms"].Status)
+	assert.False(t, dependencies["push"].Stale)
+	assert.Empty(t, events)
+
+	require.NoError(t, h.UpdateHealth("email", true, nil))
+	code, _ = h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func Test_OnStatusChange(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+
+	var checkErr error
+	h.AddHardHealthCheck("redis", testURL, func() error { return checkErr })
+	h.AddHealthCheck("email", testURL, nil)
+
+	var eventsMutex sync.Mutex
+	var events []StatusChangeEvent
+	h.OnStatusChange(func(evt StatusChangeEvent) {
+		eventsMutex.Lock()
+		defer eventsMutex.Unlock()
+		events = append(events, evt)
+	})
+
+	// the first results are not changes
+	h.(*healthCheck).runChecks(context.Background())
+	require.NoError(t, h.UpdateHealth("email", true, nil))
+	assert.Empty(t, events)
+
+	// no event when nothing changed
+	h.(*healthCheck).runChecks(context.Background())
+	assert.Empty(t, events)
+
+	checkErr = fmt.Errorf("connection refused")
+	h.(*healthCheck).runChecks(context.Background())
+	require.Len(t, events, 2)
+	assert.Equal(t, StatusChangeEvent{
+		Dependency:     "redis",
+		PreviousStatus: StatusPass,
+		Status:         StatusFail,
+		Error:          "connection refused",
+		Timestamp:      events[0].Timestamp,
+		HardDependency: true,
+	}, events[0])
+	assert.Equal(t, "", events[1].Dependency)
+	assert.Equal(t, StatusPass, events[1].PreviousStatus)
+	assert.Equal(t, StatusFail, events[1].Status)
+
+	events = nil
+	require.NoError(t, h.UpdateHealth("email", false, &CheckError{Message: "timeout"}))
+	require.Len(t, events, 1)
+	assert.Equal(t, "email", events[0].Dependency)
+	assert.Equal(t, StatusWarn, events[0].Status)
+	assert.Equal(t, "timeout", events[0].Error)
+	assert.False(t, events[0].HardDependency)
+}
+
+func Test_Draining(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("test", testURL, func() error { return nil }, WithProbes(ProbeLiveness, ProbeReadiness))
+
+	server := httptest.NewServer(h.HTTPHandler())
+	defer server.Close()
+
+	get := func(path string) (int, *response) {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body := &response{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(body))
+
+		return resp.StatusCode, body
+	}
+
+	code, body := get("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, body.Draining)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, h.Drain(ctx, time.Minute))
+
+	for _, path := range []string{"/healthz", "/readyz"} {
+		code, body = get(path)
+		assert.Equal(t, http.StatusServiceUnavailable, code, path)
+		assert.True(t, body.Draining, path)
+		assert.False(t, body.Healthy, path)
+	}
+
+	code, body = get("/livez")
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, body.Draining)
+
+	h.SetDraining(false)
+	code, _ = get("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+
+	start := time.Now()
+	require.NoError(t, h.Drain(context.Background(), 50*time.Millisecond))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Millisecond))
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+)
+
+// latencyWindowSize is the number of recent check runs used to compute the latency stats.
+const latencyWindowSize = 20
+
+// duration is a time.Duration encoded as a human readable string in JSON, e.g. "1.5ms".
+type duration time.Duration
+
+func (d duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+
+	return nil
+}
+
+// latencyStats holds the check duration stats of the recent check runs of a dependency.
+type latencyStats struct {
+	Min duration `json:"min"`
+	Max duration `json:"max"`
+	P50 duration `json:"p50"`
+	P95 duration `json:"p95"`
+}
+
+// recordDuration appends the check duration into the rolling window and recomputes the latency stats.
+func (h *healthDependency) recordDuration(elapsed time.Duration) {
+	lastDuration := duration(elapsed)
+	h.LastDuration = &lastDuration
+
+	// the window is copied instead of appended in place, since the dependency is copied around by value
+	start := 0
+	if len(h.durations) >= latencyWindowSize {
+		start = len(h.durations) - latencyWindowSize + 1
+	}
+	durations := make([]time.Duration, 0, latencyWindowSize)
+	durations = append(durations, h.durations[start:]...)
+	h.durations = append(durations, elapsed)
+
+	sorted := make([]time.Duration, len(h.durations))
+	copy(sorted, h.durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	h.Latency = &latencyStats{
+		Min: duration(sorted[0]),
+		Max: duration(sorted[len(sorted)-1]),
+		P50: duration(percentile(sorted, 0.5)),
+		P95: duration(percentile(sorted, 0.95)),
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+
+	return sorted[rank]
+}
@@ -0,0 +1,52 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordDuration(t *testing.T) {
+	d := healthDependency{}
+	for i := 1; i <= 30; i++ {
+		d.recordDuration(time.Duration(i) * time.Millisecond)
+	}
+
+	assert.Len(t, d.durations, latencyWindowSize)
+	require.NotNil(t, d.LastDuration)
+	assert.Equal(t, duration(30*time.Millisecond), *d.LastDuration)
+	require.NotNil(t, d.Latency)
+	assert.Equal(t, duration(11*time.Millisecond), d.Latency.Min)
+	assert.Equal(t, duration(30*time.Millisecond), d.Latency.Max)
+	assert.Equal(t, duration(20*time.Millisecond), d.Latency.P50)
+	assert.Equal(t, duration(29*time.Millisecond), d.Latency.P95)
+
+	body, err := json.Marshal(d.Latency)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"min":"11ms","max":"30ms","p50":"20ms","p95":"29ms"}`, string(body))
+
+	var decoded latencyStats
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, *d.Latency, decoded)
+}
+
+func TestLatencyThreshold(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("slow", testURL, func() error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}, WithLatencyThreshold(time.Millisecond))
+
+	code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusWarn, resp.Status)
+	require.Len(t, resp.Dependencies, 1)
+	assert.True(t, resp.Dependencies[0].Healthy)
+	require.NotNil(t, resp.Dependencies[0].LastError)
+	assert.Contains(t, resp.Dependencies[0].LastError.Message, "exceeding latency threshold 1ms")
+}
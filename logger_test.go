@@ -0,0 +1,113 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logEntry struct {
+	level  string
+	msg    string
+	fields []interface{}
+}
+
+type testLogger struct {
+	mutex   sync.Mutex
+	entries []logEntry
+}
+
+func (l *testLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.log("info", msg, keysAndValues)
+}
+
+func (l *testLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log("warn", msg, keysAndValues)
+}
+
+func (l *testLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log("error", msg, keysAndValues)
+}
+
+func (l *testLogger) log(level, msg string, keysAndValues []interface{}) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.entries = append(l.entries, logEntry{level: level, msg: msg, fields: keysAndValues})
+}
+
+func Test_LoggerCheckFailureAndRecovery(t *testing.T) {
+	logger := &testLogger{}
+	checkErr := errors.New("connection refused")
+	var failing bool
+
+	h := New(&Config{ServiceName: serviceName, Logger: logger}).(*healthCheck)
+	h.AddHardHealthCheck("mongo", testURL, func() error {
+		if failing {
+			return checkErr
+		}
+
+		return nil
+	})
+
+	failing = true
+	h.getResponse(context.Background(), "", "")
+	// a dependency which stays down is only logged once
+	h.getResponse(context.Background(), "", "")
+	failing = false
+	h.getResponse(context.Background(), "", "")
+	h.getResponse(context.Background(), "", "")
+
+	require.Len(t, logger.entries, 2)
+	assert.Equal(t, logEntry{
+		level:  "error",
+		msg:    "Dependency health check failed",
+		fields: []interface{}{"dependency", "mongo", "error", checkErr, "consecutiveFailures", 1},
+	}, logger.entries[0])
+	assert.Equal(t, logEntry{
+		level:  "info",
+		msg:    "Dependency health check recovered",
+		fields: []interface{}{"dependency", "mongo"},
+	}, logger.entries[1])
+}
+
+func Test_LoggerSuccessThreshold(t *testing.T) {
+	logger := &testLogger{}
+
+	h := New(&Config{ServiceName: serviceName, Logger: logger}).(*healthCheck)
+	h.AddHardHealthCheck("mongo", testURL, func() error { return nil }, WithSuccessThreshold(2))
+
+	h.getResponse(context.Background(), "", "")
+	h.getResponse(context.Background(), "", "")
+
+	// the first success is not reported as a failure
+	require.Len(t, logger.entries, 2)
+	assert.Equal(t, logEntry{
+		level:  "info",
+		msg:    "Dependency health check waiting for the success threshold",
+		fields: []interface{}{"dependency", "mongo", "consecutiveSuccesses", 1, "successThreshold", 2},
+	}, logger.entries[0])
+	assert.Equal(t, logEntry{
+		level:  "info",
+		msg:    "Dependency health check recovered",
+		fields: []interface{}{"dependency", "mongo"},
+	}, logger.entries[1])
+}
+
+func TestLogrusLogger(t *testing.T) {
+	logrusLogger, hook := logrustest.NewNullLogger()
+	logger := NewLogrusLogger(logrusLogger)
+
+	logger.Error("Dependency health check failed", "dependency", "mongo", "error", "timeout")
+
+	require.Len(t, hook.Entries, 1)
+	assert.Equal(t, logrus.ErrorLevel, hook.LastEntry().Level)
+	assert.Equal(t, "Dependency health check failed", hook.LastEntry().Message)
+	assert.Equal(t, logrus.Fields{"dependency": "mongo", "error": "timeout"}, hook.LastEntry().Data)
+}
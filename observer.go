@@ -0,0 +1,47 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import "context"
+
+// CheckObserver observes the check runs, e.g. to trace and measure them. See the otelhealth package for the
+// OpenTelemetry implementation.
+type CheckObserver interface {
+	// StartRun is called when a check run of one or more dependencies starts. The returned context is passed to the
+	// checks of the run and the returned function is called when the run ends.
+	StartRun(ctx context.Context) (context.Context, func())
+
+	// StartCheck is called when a dependency check starts. The returned context is passed to the check function and
+	// the returned function is called with the check error, nil when the check succeeded.
+	StartCheck(ctx context.Context, check CheckInfo) (context.Context, func(err error))
+}
+
+// CheckInfo describes the dependency being checked.
+type CheckInfo struct {
+	Name           string
+	URL            string
+	HardDependency bool
+}
+
+// noopObserver is the default CheckObserver.
+type noopObserver struct{}
+
+func (noopObserver) StartRun(ctx context.Context) (context.Context, func()) {
+	return ctx, func() {}
+}
+
+func (noopObserver) StartCheck(ctx context.Context, _ CheckInfo) (context.Context, func(err error)) {
+	return ctx, func(error) {}
+}
@@ -0,0 +1,124 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelhealth traces and measures the health check runs with OpenTelemetry.
+package otelhealth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+
+	healthcheck "github.com/AccelByte/healthcheck-go-sdk/v2"
+)
+
+const instrumentationName = "github.com/AccelByte/healthcheck-go-sdk/v2"
+
+const (
+	checkResultSuccess  = "success"
+	checkResultFailure  = "failure"
+	checkResultDegraded = "degraded"
+)
+
+// observer holds the OpenTelemetry tracer and metric instruments of the check runs.
+type observer struct {
+	tracer        trace.Tracer
+	checkDuration metric.Float64Histogram
+	checks        metric.Int64Counter
+}
+
+// NewObserver returns a check observer creating a healthcheck.runChecks span per check run with a healthcheck.check
+// child span per dependency check, and recording the check duration and result as metrics. A nil provider defaults to
+// a no-op implementation. Set it as the Observer of the health check Config.
+func NewObserver(tracerProvider trace.TracerProvider,
+	meterProvider metric.MeterProvider) (healthcheck.CheckObserver, error) {
+	if tracerProvider == nil {
+		tracerProvider = trace.NewNoopTracerProvider()
+	}
+
+	if meterProvider == nil {
+		meterProvider = noop.NewMeterProvider()
+	}
+
+	meter := meterProvider.Meter(instrumentationName)
+
+	checkDuration, err := meter.Float64Histogram("healthcheck.check.duration",
+		metric.WithDescription("Duration of the dependency checks."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+
+	checks, err := meter.Int64Counter("healthcheck.check.executions",
+		metric.WithDescription("Number of dependency check executions by result."))
+	if err != nil {
+		return nil, err
+	}
+
+	return &observer{
+		tracer:        tracerProvider.Tracer(instrumentationName),
+		checkDuration: checkDuration,
+		checks:        checks,
+	}, nil
+}
+
+// StartRun starts the span of a check run.
+func (o *observer) StartRun(ctx context.Context) (context.Context, func()) {
+	ctx, span := o.tracer.Start(ctx, "healthcheck.runChecks")
+
+	return ctx, func() { span.End() }
+}
+
+// StartCheck starts the span of a dependency check, the returned function ends it and records the check metrics.
+func (o *observer) StartCheck(ctx context.Context, d healthcheck.CheckInfo) (context.Context, func(err error)) {
+	attributes := []attribute.KeyValue{
+		attribute.String("dependency.name", d.Name),
+		attribute.String("dependency.url", d.URL),
+		attribute.Bool("dependency.hard", d.HardDependency),
+	}
+
+	ctx, span := o.tracer.Start(ctx, "healthcheck.check", trace.WithAttributes(attributes...))
+	start := time.Now()
+
+	return ctx, func(err error) {
+		result := checkResultSuccess
+
+		var degradedErr *healthcheck.DegradedError
+		switch {
+		case errors.As(err, &degradedErr):
+			result = checkResultDegraded
+			span.RecordError(err)
+		case err != nil:
+			result = checkResultFailure
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.SetAttributes(attribute.String("check.result", result))
+		span.End()
+
+		// the url is left out of the metric attributes to keep the cardinality low
+		metricAttributes := metric.WithAttributes(
+			attribute.String("dependency.name", d.Name),
+			attribute.Bool("dependency.hard", d.HardDependency),
+			attribute.String("check.result", result))
+		o.checkDuration.Record(ctx, time.Since(start).Seconds(), metricAttributes)
+		o.checks.Add(ctx, 1, metricAttributes)
+	}
+}
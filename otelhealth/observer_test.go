@@ -0,0 +1,135 @@
+package otelhealth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	healthcheck "github.com/AccelByte/healthcheck-go-sdk/v2"
+)
+
+const (
+	testURL     = "www.test.example.com"
+	serviceName = "test"
+)
+
+func TestObserver(t *testing.T) {
+	spanExporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter))
+	metricReader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(metricReader))
+
+	observer, err := NewObserver(tracerProvider, meterProvider)
+	require.NoError(t, err)
+
+	h := healthcheck.New(&healthcheck.Config{ServiceName: serviceName, Observer: observer})
+	h.AddHardHealthCheck("redis", testURL, func() error { return nil })
+	h.AddHealthCheck("elastic", testURL, func() error { return fmt.Errorf("error") })
+
+	h.Refresh(context.Background())
+
+	spans := spanExporter.GetSpans()
+	require.Len(t, spans, 3)
+
+	var root tracetest.SpanStub
+	for _, span := range spans {
+		if span.Name == "healthcheck.runChecks" {
+			root = span
+		}
+	}
+	require.Equal(t, "healthcheck.runChecks", root.Name)
+
+	for _, span := range spans {
+		if span.Name != "healthcheck.check" {
+			continue
+		}
+
+		assert.Equal(t, root.SpanContext.SpanID(), span.Parent.SpanID())
+		attributes := attribute.NewSet(span.Attributes...)
+		name, _ := attributes.Value("dependency.name")
+		url, _ := attributes.Value("dependency.url")
+		assert.Equal(t, testURL, url.AsString())
+
+		if name.AsString() == "elastic" {
+			assert.Equal(t, codes.Error, span.Status.Code)
+			hard, _ := attributes.Value("dependency.hard")
+			assert.False(t, hard.AsBool())
+		} else {
+			assert.Equal(t, codes.Unset, span.Status.Code)
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, metricReader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	executions, ok := metrics["healthcheck.check.executions"].Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, executions.DataPoints, 2)
+	for _, dataPoint := range executions.DataPoints {
+		assert.Equal(t, int64(1), dataPoint.Value)
+
+		name, _ := dataPoint.Attributes.Value("dependency.name")
+		result, _ := dataPoint.Attributes.Value("check.result")
+		if name.AsString() == "elastic" {
+			assert.Equal(t, checkResultFailure, result.AsString())
+		} else {
+			assert.Equal(t, checkResultSuccess, result.AsString())
+		}
+	}
+
+	durations, ok := metrics["healthcheck.check.duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	assert.Len(t, durations.DataPoints, 2)
+}
+
+func TestObserverBackgroundCheck(t *testing.T) {
+	spanExporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter))
+
+	observer, err := NewObserver(tracerProvider, nil)
+	require.NoError(t, err)
+
+	h := healthcheck.New(&healthcheck.Config{ServiceName: serviceName, Observer: observer})
+	h.AddHardHealthCheck("redis", testURL, func() error { return nil }, healthcheck.WithInterval(20*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h.StartBackgroundCheck(ctx)
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	// let the pass in progress end its span
+	time.Sleep(20 * time.Millisecond)
+
+	roots := map[trace.SpanID]bool{}
+	var checks []tracetest.SpanStub
+	for _, span := range spanExporter.GetSpans() {
+		switch span.Name {
+		case "healthcheck.runChecks":
+			roots[span.SpanContext.SpanID()] = true
+		case "healthcheck.check":
+			checks = append(checks, span)
+		}
+	}
+
+	// the scheduled checks after the first run are traced as well
+	require.Greater(t, len(checks), 1)
+	for _, span := range checks {
+		assert.True(t, roots[span.Parent.SpanID()])
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"errors"
+	"time"
+)
+
+// errorRateBuckets is the number of buckets the error rate window is divided into.
+const errorRateBuckets = 10
+
+var errNoErrorRate = errors.New("dependency is not registered with WithErrorRate")
+
+// errorRateWindow counts the results recorded within a sliding time window, divided into buckets.
+type errorRateWindow struct {
+	window       time.Duration
+	bucketSize   time.Duration
+	maxErrorRate float64
+	minSamples   int
+	buckets      [errorRateBuckets]errorRateBucket
+}
+
+type errorRateBucket struct {
+	start    time.Time
+	requests int
+	errors   int
+}
+
+// errorRateStats is the error rate of the results within the window reported in the health response.
+type errorRateStats struct {
+	Window   duration `json:"window"`
+	Requests int      `json:"requests"`
+	Errors   int      `json:"errors"`
+	Rate     float64  `json:"rate"`
+}
+
+// WithErrorRate computes the dependency health from the results recorded using RecordResult within the sliding
+// window. The dependency is unhealthy when the error rate, from 0 to 1, exceeds maxErrorRate with at least
+// minSamples results within the window. As the results age out of the window, the health change is reported to the
+// watchers and the OnStatusChange listeners without waiting for another result.
+func WithErrorRate(window time.Duration, maxErrorRate float64, minSamples int) DependencyOption {
+	return func(d *healthDependency) {
+		bucketSize := window / errorRateBuckets
+		if bucketSize <= 0 {
+			bucketSize = 1
+		}
+
+		d.errorRate = &errorRateWindow{
+			window:       window,
+			bucketSize:   bucketSize,
+			maxErrorRate: maxErrorRate,
+			minSamples:   minSamples,
+		}
+	}
+}
+
+func (w *errorRateWindow) record(now time.Time, failed bool) {
+	start := now.Truncate(w.bucketSize)
+	bucket := &w.buckets[(start.UnixNano()/int64(w.bucketSize))%errorRateBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = errorRateBucket{start: start}
+	}
+
+	bucket.requests++
+	if failed {
+		bucket.errors++
+	}
+}
+
+func (w *errorRateWindow) stats(now time.Time) errorRateStats {
+	stats := errorRateStats{Window: duration(w.window)}
+	for _, bucket := range w.buckets {
+		if now.Sub(bucket.start) < w.window {
+			stats.Requests += bucket.requests
+			stats.Errors += bucket.errors
+		}
+	}
+
+	if stats.Requests > 0 {
+		stats.Rate = float64(stats.Errors) / float64(stats.Requests)
+	}
+
+	return stats
+}
+
+// nextExpiry returns when the oldest results within the window age out of it, if any.
+func (w *errorRateWindow) nextExpiry(now time.Time) (time.Time, bool) {
+	var next time.Time
+	for _, bucket := range w.buckets {
+		if bucket.requests == 0 || now.Sub(bucket.start) >= w.window {
+			continue
+		}
+
+		if expiry := bucket.start.Add(w.window); next.IsZero() || expiry.Before(next) {
+			next = expiry
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+// healthy returns false when the error rate exceeds the maximum with enough samples.
+func (w *errorRateWindow) healthy(stats errorRateStats) bool {
+	return stats.Requests < w.minSamples || stats.Rate <= w.maxErrorRate
+}
+
+// RecordResult records the result of a call to a dependency registered with WithErrorRate, a nil error meaning
+// a success.
+func (h *healthCheck) RecordResult(name string, err error) error {
+	h.dependenciesMutex.Lock()
+
+	dependency, exist := h.dependencies[name]
+	if !exist {
+		h.dependenciesMutex.Unlock()
+
+		return errDependencyNotFound
+	}
+	if dependency.errorRate == nil {
+		h.dependenciesMutex.Unlock()
+
+		return errNoErrorRate
+	}
+
+	now := time.Now()
+	dependency.errorRate.record(now, err != nil)
+	dependency.Healthy = dependency.errorRate.healthy(dependency.errorRate.stats(now))
+	dependency.degraded = false
+	dependency.LastCall = &now
+	if err == nil {
+		dependency.LastKnownGoodCall = dependency.LastCall
+		dependency.ConsecutiveSuccesses++
+		dependency.ConsecutiveFailures = 0
+	} else {
+		dependency.LastError = &lastError{Message: err.Error(), Timestamp: dependency.LastCall}
+		dependency.ConsecutiveFailures++
+		dependency.ConsecutiveSuccesses = 0
+	}
+	events := h.storeDependencyLocked(dependency)
+	h.dependenciesMutex.Unlock()
+
+	h.notifyWatchers()
+	h.fireStatusChange(events)
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RecordResult(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("email", testURL, nil, WithErrorRate(200*time.Millisecond, 0.5, 4))
+	h.AddHealthCheck("sms", testURL, nil)
+
+	assert.ErrorIs(t, h.RecordResult("unknown", nil), errDependencyNotFound)
+	assert.ErrorIs(t, h.RecordResult("sms", nil), errNoErrorRate)
+
+	getEmail := func() (int, healthDependency) {
+		code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+		for _, d := range resp.Dependencies {
+			if d.Name == "email" {
+				return code, d
+			}
+		}
+		require.Fail(t, "email is not reported")
+
+		return code, healthDependency{}
+	}
+
+	// a failure among successes does not flip the dependency
+	require.NoError(t, h.RecordResult("email", nil))
+	require.NoError(t, h.RecordResult("email", nil))
+	require.NoError(t, h.RecordResult("email", fmt.Errorf("connection reset")))
+	code, email := getEmail()
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, email.Healthy)
+	require.NotNil(t, email.ErrorRate)
+	assert.Equal(t, errorRateStats{Window: duration(200 * time.Millisecond), Requests: 3, Errors: 1, Rate: 1.0 / 3},
+		*email.ErrorRate)
+
+	// not enough samples yet
+	require.NoError(t, h.RecordResult("email", fmt.Errorf("connection reset")))
+	code, _ = getEmail()
+	assert.Equal(t, http.StatusOK, code)
+
+	require.NoError(t, h.RecordResult("email", fmt.Errorf("connection reset")))
+	code, email = getEmail()
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, email.Healthy)
+	assert.Equal(t, 3, email.ErrorRate.Errors)
+	assert.Equal(t, "connection reset", email.LastError.Message)
+
+	events := make(chan StatusChangeEvent, 10)
+	h.OnStatusChange(func(evt StatusChangeEvent) {
+		events <- evt
+	})
+
+	// the errors age out of the window, which is reported without any new result
+	select {
+	case evt := <-events:
+		assert.Equal(t, "email", evt.Dependency)
+		assert.Equal(t, StatusFail, evt.PreviousStatus)
+		assert.Equal(t, StatusPass, evt.Status)
+	case <-time.After(time.Second):
+		require.Fail(t, "error rate recovery is not reported")
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	code, email = getEmail()
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 0, email.ErrorRate.Requests)
+}
+
+func Test_ErrorRateWindow(t *testing.T) {
+	w := &errorRateWindow{window: 10 * time.Second, bucketSize: time.Second, maxErrorRate: 0.1, minSamples: 1}
+	now := time.Unix(1000, 0)
+
+	w.record(now, true)
+	w.record(now.Add(5*time.Second), false)
+	w.record(now.Add(9*time.Second), false)
+	assert.Equal(t, 3, w.stats(now.Add(9*time.Second)).Requests)
+
+	// the first bucket slides out of the window and its slot is reused
+	stats := w.stats(now.Add(10 * time.Second))
+	assert.Equal(t, 2, stats.Requests)
+	assert.Equal(t, 0, stats.Errors)
+
+	w.record(now.Add(10*time.Second), false)
+	stats = w.stats(now.Add(10 * time.Second))
+	assert.Equal(t, 3, stats.Requests)
+	assert.True(t, w.healthy(stats))
+
+	expiry, ok := w.nextExpiry(now.Add(10 * time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(15*time.Second), expiry)
+
+	_, ok = w.nextExpiry(now.Add(20 * time.Second))
+	assert.False(t, ok)
+}
@@ -0,0 +1,112 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package promhealth exports the health check dependencies health as Prometheus metrics.
+package promhealth
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	healthcheck "github.com/AccelByte/healthcheck-go-sdk/v2"
+)
+
+const metricsNamespace = "healthcheck"
+
+var metricLabels = []string{"service", "dependency", "type"}
+
+// prometheusCollector exports the dependencies health as Prometheus metrics. The values are read from the
+// dependencies on every scrape, hence always reflect the last check results.
+type collector struct {
+	h healthcheck.Handler
+
+	healthy                       *prometheus.Desc
+	lastCheckDuration             *prometheus.Desc
+	consecutiveFailures           *prometheus.Desc
+	secondsSinceLastKnownGoodCall *prometheus.Desc
+	checks                        *prometheus.Desc
+	checkFailures                 *prometheus.Desc
+}
+
+// NewCollector returns a Prometheus collector exporting the dependencies health, check duration and check counts,
+// which can be registered into any Prometheus registry.
+func NewCollector(h healthcheck.Handler) prometheus.Collector {
+	return &collector{
+		h: h,
+		healthy: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "dependency", "healthy"),
+			"Whether the dependency is healthy (1) or not (0).", metricLabels, nil),
+		lastCheckDuration: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "dependency", "last_check_duration_seconds"),
+			"Duration of the last dependency check.", metricLabels, nil),
+		consecutiveFailures: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "dependency", "consecutive_failures"),
+			"Number of consecutive failed dependency checks.", metricLabels, nil),
+		secondsSinceLastKnownGoodCall: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "dependency", "seconds_since_last_known_good_call"),
+			"Seconds since the last successful dependency check.", metricLabels, nil),
+		checks: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "dependency", "checks_total"),
+			"Number of dependency check executions.", metricLabels, nil),
+		checkFailures: prometheus.NewDesc(prometheus.BuildFQName(metricsNamespace, "dependency", "check_failures_total"),
+			"Number of failed dependency check executions.", metricLabels, nil),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.healthy
+	ch <- c.lastCheckDuration
+	ch <- c.consecutiveFailures
+	ch <- c.secondsSinceLastKnownGoodCall
+	ch <- c.checks
+	ch <- c.checkFailures
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+
+	serviceName := c.h.ServiceName()
+
+	for _, d := range c.h.Dependencies() {
+		labels := []string{serviceName, d.Name, dependencyType(d.HardDependency)}
+
+		healthy := 0.0
+		if d.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.healthy, prometheus.GaugeValue, healthy, labels...)
+		ch <- prometheus.MustNewConstMetric(c.consecutiveFailures, prometheus.GaugeValue,
+			float64(d.ConsecutiveFailures), labels...)
+		ch <- prometheus.MustNewConstMetric(c.checks, prometheus.CounterValue, float64(d.Checks), labels...)
+		ch <- prometheus.MustNewConstMetric(c.checkFailures, prometheus.CounterValue, float64(d.CheckFailures),
+			labels...)
+
+		if d.LastDuration != nil {
+			ch <- prometheus.MustNewConstMetric(c.lastCheckDuration, prometheus.GaugeValue,
+				d.LastDuration.Seconds(), labels...)
+		}
+
+		if d.LastKnownGoodCall != nil {
+			ch <- prometheus.MustNewConstMetric(c.secondsSinceLastKnownGoodCall, prometheus.GaugeValue,
+				now.Sub(*d.LastKnownGoodCall).Seconds(), labels...)
+		}
+	}
+}
+
+func dependencyType(hardDependency bool) string {
+	if hardDependency {
+		return "hard"
+	}
+
+	return "soft"
+}
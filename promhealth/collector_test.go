@@ -0,0 +1,59 @@
+package promhealth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	healthcheck "github.com/AccelByte/healthcheck-go-sdk/v2"
+)
+
+const (
+	testURL     = "www.test.example.com"
+	serviceName = "test"
+)
+
+func TestCollector(t *testing.T) {
+	h := healthcheck.New(&healthcheck.Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("redis", testURL, func() error { return nil })
+	h.AddHealthCheck("elastic", testURL, func() error { return fmt.Errorf("error") })
+
+	h.Refresh(context.Background())
+	h.Refresh(context.Background())
+
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(NewCollector(h)))
+
+	expected := `
+# HELP healthcheck_dependency_healthy Whether the dependency is healthy (1) or not (0).
+# TYPE healthcheck_dependency_healthy gauge
+healthcheck_dependency_healthy{dependency="elastic",service="test",type="soft"} 0
+healthcheck_dependency_healthy{dependency="redis",service="test",type="hard"} 1
+# HELP healthcheck_dependency_consecutive_failures Number of consecutive failed dependency checks.
+# TYPE healthcheck_dependency_consecutive_failures gauge
+healthcheck_dependency_consecutive_failures{dependency="elastic",service="test",type="soft"} 2
+healthcheck_dependency_consecutive_failures{dependency="redis",service="test",type="hard"} 0
+# HELP healthcheck_dependency_checks_total Number of dependency check executions.
+# TYPE healthcheck_dependency_checks_total counter
+healthcheck_dependency_checks_total{dependency="elastic",service="test",type="soft"} 2
+healthcheck_dependency_checks_total{dependency="redis",service="test",type="hard"} 2
+# HELP healthcheck_dependency_check_failures_total Number of failed dependency check executions.
+# TYPE healthcheck_dependency_check_failures_total counter
+healthcheck_dependency_check_failures_total{dependency="elastic",service="test",type="soft"} 2
+healthcheck_dependency_check_failures_total{dependency="redis",service="test",type="hard"} 0
+`
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"healthcheck_dependency_healthy", "healthcheck_dependency_consecutive_failures",
+		"healthcheck_dependency_checks_total", "healthcheck_dependency_check_failures_total"))
+
+	count, err := testutil.GatherAndCount(registry, "healthcheck_dependency_last_check_duration_seconds",
+		"healthcheck_dependency_seconds_since_last_known_good_call")
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
@@ -0,0 +1,91 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"fmt"
+)
+
+// quorumRule requires at least minHealthy of the dependencies to be healthy.
+type quorumRule struct {
+	name         string
+	minHealthy   int
+	dependencies []string
+}
+
+// ruleResult is the evaluation result of a quorum rule reported in the health response.
+type ruleResult struct {
+	Name                string   `json:"name"`
+	Healthy             bool     `json:"healthy"`
+	Status              Status   `json:"status"`
+	MinHealthy          int      `json:"minHealthy"`
+	HealthyDependencies int      `json:"healthyDependencies"`
+	Dependencies        []string `json:"dependencies"`
+}
+
+// AddQuorumRule adds a rule requiring at least minHealthy of the dependencies to be healthy, e.g. 2 of 3 redundant
+// shards. The service status fails when the rule is not met, hence the dependencies are usually registered as soft
+// dependencies. Adding a rule with an existing name replaces it.
+func (h *healthCheck) AddQuorumRule(name string, minHealthy int, dependencies ...string) error {
+	if minHealthy < 1 || minHealthy > len(dependencies) {
+		return fmt.Errorf("quorum rule %s requires between 1 and %d healthy dependencies, got %d",
+			name, len(dependencies), minHealthy)
+	}
+
+	h.dependenciesMutex.Lock()
+	defer h.dependenciesMutex.Unlock()
+
+	for _, dependency := range dependencies {
+		if _, exist := h.dependencies[dependency]; !exist {
+			return fmt.Errorf("quorum rule %s: %w: %s", name, errDependencyNotFound, dependency)
+		}
+	}
+
+	rule := quorumRule{name: name, minHealthy: minHealthy, dependencies: dependencies}
+	for i := range h.rules {
+		if h.rules[i].name == name {
+			h.rules[i] = rule
+
+			return nil
+		}
+	}
+	h.rules = append(h.rules, rule)
+
+	return nil
+}
+
+// evaluateRuleLocked evaluates the rule with the reported dependencies health.
+// dependenciesMutex must be held by the caller.
+func (h *healthCheck) evaluateRuleLocked(rule quorumRule) ruleResult {
+	result := ruleResult{
+		Name:         rule.name,
+		MinHealthy:   rule.minHealthy,
+		Dependencies: rule.dependencies,
+	}
+
+	for _, name := range rule.dependencies {
+		if d, exist := h.dependencies[name]; exist && h.reportedLocked(d).Healthy {
+			result.HealthyDependencies++
+		}
+	}
+
+	result.Healthy = result.HealthyDependencies >= rule.minHealthy
+	result.Status = StatusPass
+	if !result.Healthy {
+		result.Status = StatusFail
+	}
+
+	return result
+}
@@ -0,0 +1,75 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_QuorumRule(t *testing.T) {
+	h := New(&Config{ServiceName: serviceName})
+
+	shardErrs := map[string]error{}
+	for _, shard := range []string{"redis-0", "redis-1", "redis-2"} {
+		shard := shard
+		h.AddHealthCheck(shard, testURL, func() error { return shardErrs[shard] }, WithTags("cache"))
+	}
+	h.AddHardHealthCheck("kafka", testURL, func() error { return nil }, WithTags("messaging"))
+
+	assert.Error(t, h.AddQuorumRule("redis", 4, "redis-0", "redis-1", "redis-2"))
+	assert.Error(t, h.AddQuorumRule("redis", 0, "redis-0", "redis-1", "redis-2"))
+	assert.ErrorIs(t, h.AddQuorumRule("redis", 1, "redis-0", "unknown"), errDependencyNotFound)
+	require.NoError(t, h.AddQuorumRule("redis", 2, "redis-0", "redis-1", "redis-2"))
+
+	var events []StatusChangeEvent
+	h.OnStatusChange(func(evt StatusChangeEvent) {
+		events = append(events, evt)
+	})
+
+	code, resp := h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusPass, resp.Status)
+	assert.Equal(t, []ruleResult{{
+		Name:                "redis",
+		Healthy:             true,
+		Status:              StatusPass,
+		MinHealthy:          2,
+		HealthyDependencies: 3,
+		Dependencies:        []string{"redis-0", "redis-1", "redis-2"},
+	}}, resp.Rules)
+
+	// losing a single shard only degrades the service
+	shardErrs["redis-0"] = fmt.Errorf("connection refused")
+	code, resp = h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusWarn, resp.Status)
+	require.Len(t, resp.Rules, 1)
+	assert.True(t, resp.Rules[0].Healthy)
+	assert.Equal(t, 2, resp.Rules[0].HealthyDependencies)
+
+	// losing the quorum fails the service
+	shardErrs["redis-1"] = fmt.Errorf("connection refused")
+	events = nil
+	code, resp = h.(*healthCheck).getResponse(context.Background(), "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFail, resp.Status)
+	require.Len(t, resp.Rules, 1)
+	assert.False(t, resp.Rules[0].Healthy)
+	assert.Equal(t, StatusFail, resp.Rules[0].Status)
+	require.NotEmpty(t, events)
+	assert.Equal(t, "", events[len(events)-1].Dependency)
+	assert.Equal(t, StatusFail, events[len(events)-1].Status)
+
+	// the rule is only reported along with its dependencies
+	code, resp = h.(*healthCheck).getResponse(context.Background(), "", "messaging")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, resp.Rules)
+
+	code, resp = h.(*healthCheck).getResponse(context.Background(), "", "cache")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Len(t, resp.Rules, 1)
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TransportOption configures the round tripper returned by Transport.
+type TransportOption func(t *transport)
+
+// WithFailureStatus sets which response status codes count as a dependency failure. Defaults to 5xx.
+func WithFailureStatus(isFailure func(statusCode int) bool) TransportOption {
+	return func(t *transport) {
+		t.isFailure = isFailure
+	}
+}
+
+type transport struct {
+	h         Handler
+	name      string
+	base      http.RoundTripper
+	isFailure func(statusCode int) bool
+}
+
+// Transport wraps the base round tripper, defaulting to http.DefaultTransport, to record the result of every request
+// as the health of the dependency. A transport error or a failure status code is recorded as a failure, using
+// RecordResult for a dependency registered with WithErrorRate, otherwise UpdateHealth.
+func Transport(h Handler, name string, base http.RoundTripper, opts ...TransportOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	t := &transport{
+		h:    h,
+		name: name,
+		base: base,
+		isFailure: func(statusCode int) bool {
+			return statusCode >= http.StatusInternalServerError
+		},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+
+	// a request canceled by the caller says nothing about the dependency, unlike a request timing out
+	if errors.Is(req.Context().Err(), context.Canceled) {
+		return resp, err
+	}
+
+	result := err
+	if result == nil && t.isFailure(resp.StatusCode) {
+		result = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	t.record(result)
+
+	return resp, err
+}
+
+func (t *transport) record(result error) {
+	if err := t.h.RecordResult(t.name, result); !errors.Is(err, errNoErrorRate) {
+		return
+	}
+
+	var checkError *CheckError
+	if result != nil {
+		checkError = &CheckError{Timestamp: time.Now(), Message: result.Error()}
+	}
+	_ = t.h.UpdateHealth(t.name, result == nil, checkError)
+}
@@ -0,0 +1,91 @@
+package healthcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Transport(t *testing.T) {
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	h := New(&Config{ServiceName: serviceName})
+	h.AddHardHealthCheck("email", server.URL, nil)
+	h.AddHardHealthCheck("sms", server.URL, nil, WithErrorRate(time.Minute, 0.5, 1))
+	h.AddHardHealthCheck("push", server.URL, nil)
+
+	getDependency := func(name string) healthDependency {
+		h.(*healthCheck).dependenciesMutex.RLock()
+		defer h.(*healthCheck).dependenciesMutex.RUnlock()
+
+		return h.(*healthCheck).reportedLocked(h.(*healthCheck).dependencies[name])
+	}
+
+	get := func(client *http.Client, path string) {
+		resp, err := client.Get(server.URL + path)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}
+
+	email := &http.Client{Transport: Transport(h, "email", nil)}
+	get(email, "/")
+	assert.True(t, getDependency("email").Healthy)
+
+	statusCode = http.StatusBadGateway
+	get(email, "/")
+	assert.False(t, getDependency("email").Healthy)
+	assert.Equal(t, "unexpected status code 502", getDependency("email").LastError.Message)
+
+	// a client error is not a dependency failure by default
+	statusCode = http.StatusNotFound
+	get(email, "/")
+	assert.True(t, getDependency("email").Healthy)
+
+	statusCode = http.StatusTooManyRequests
+	sms := &http.Client{Transport: Transport(h, "sms", http.DefaultTransport, WithFailureStatus(func(code int) bool {
+		return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}))}
+	get(sms, "/")
+	get(sms, "/")
+	statusCode = http.StatusOK
+	get(sms, "/")
+	d := getDependency("sms")
+	require.NotNil(t, d.ErrorRate)
+	assert.Equal(t, 3, d.ErrorRate.Requests)
+	assert.Equal(t, 2, d.ErrorRate.Errors)
+	assert.False(t, d.Healthy)
+
+	// a transport error is a failure
+	push := &http.Client{Transport: Transport(h, "push", nil), Timeout: 50 * time.Millisecond}
+	get(push, "/")
+	assert.True(t, getDependency("push").Healthy)
+	get(push, "/slow")
+	assert.False(t, getDependency("push").Healthy)
+
+	// a request canceled by the caller is not recorded
+	get(push, "/")
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/slow", nil)
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	_, err = (&http.Client{Transport: Transport(h, "push", nil)}).Do(req)
+	assert.Error(t, err)
+	assert.True(t, getDependency("push").Healthy)
+}
@@ -15,19 +15,86 @@
 package healthcheck
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
 	"sync"
 	"time"
 )
 
 type healthDependency struct {
-	Name              string     `json:"name"`
-	URL               string     `json:"url"`
-	Healthy           bool       `json:"healthy"`
-	HardDependency    bool       `json:"hardDependency"`
-	LastKnownGoodCall *time.Time `json:"lastKnownGoodCall,omitempty"`
-	LastCall          *time.Time `json:"lastCall,omitempty"`
-	LastError         *lastError `json:"lastError,omitempty"`
-	checkFunc         CheckFunc
+	Name                 string          `json:"name"`
+	URL                  string          `json:"url"`
+	Healthy              bool            `json:"healthy"`
+	Status               Status          `json:"status"`
+	HardDependency       bool            `json:"hardDependency"`
+	LastKnownGoodCall    *time.Time      `json:"lastKnownGoodCall,omitempty"`
+	LastCall             *time.Time      `json:"lastCall,omitempty"`
+	LastError            *lastError      `json:"lastError,omitempty"`
+	ConsecutiveFailures  int             `json:"consecutiveFailures"`
+	ConsecutiveSuccesses int             `json:"consecutiveSuccesses"`
+	LastDuration         *duration       `json:"lastDuration,omitempty"`
+	Latency              *latencyStats   `json:"latency,omitempty"`
+	Overridden           bool            `json:"overridden,omitempty"`
+	OverrideReason       string          `json:"overrideReason,omitempty"`
+	OverrideExpiresAt    *time.Time      `json:"overrideExpiresAt,omitempty"`
+	Tags                 []string        `json:"tags,omitempty"`
+	DependsOn            []string        `json:"dependsOn,omitempty"`
+	BlockedBy            string          `json:"blockedBy,omitempty"`
+	Stale                bool            `json:"stale,omitempty"`
+	ErrorRate            *errorRateStats `json:"errorRate,omitempty"`
+	checkFunc            CheckFuncWithContext
+	probes               []Probe
+	timeout              time.Duration
+	interval             time.Duration
+	failureThreshold     int
+	successThreshold     int
+	degraded             bool
+	latencyThreshold     time.Duration
+	staleAfter           time.Duration
+	staleHealthy         bool
+	errorRate            *errorRateWindow
+	durations            []time.Duration
+	checks               uint64
+	checkFailures        uint64
+}
+
+// DependencyHealth is the reported health of a dependency.
+type DependencyHealth struct {
+	Name                string
+	URL                 string
+	HardDependency      bool
+	Healthy             bool
+	Status              Status
+	ConsecutiveFailures int
+	Checks              uint64
+	CheckFailures       uint64
+	LastDuration        *time.Duration
+	LastKnownGoodCall   *time.Time
+}
+
+// health returns the exported health of the dependency.
+func (h *healthDependency) health() DependencyHealth {
+	health := DependencyHealth{
+		Name:                h.Name,
+		URL:                 h.URL,
+		HardDependency:      h.HardDependency,
+		Healthy:             h.Healthy,
+		Status:              h.status(),
+		ConsecutiveFailures: h.ConsecutiveFailures,
+		Checks:              h.checks,
+		CheckFailures:       h.checkFailures,
+		LastKnownGoodCall:   h.LastKnownGoodCall,
+	}
+
+	if h.LastDuration != nil {
+		lastDuration := time.Duration(*h.LastDuration)
+		health.LastDuration = &lastDuration
+	}
+
+	return health
 }
 
 // CheckError holds error information result of a dependency check submitted via UpdateHealth API.
@@ -36,50 +103,394 @@ type CheckError struct {
 	Message   string
 }
 
+// Status is a tri-state health status of a dependency or the service, a dependency which check is skipped because
+// of an unhealthy upstream dependency is reported as skipped.
+type Status string
+
+const (
+	// StatusPass means healthy.
+	StatusPass Status = "pass"
+	// StatusWarn means running with reduced function, caused by an unhealthy soft dependency or a degraded dependency.
+	StatusWarn Status = "warn"
+	// StatusFail means unhealthy, caused by an unhealthy hard dependency.
+	StatusFail Status = "fail"
+	// StatusSkipped means the dependency check is skipped because an upstream dependency is unhealthy. It affects the
+	// service status like a failed check does, i.e. fail for a hard dependency and warn for a soft one.
+	StatusSkipped Status = "skipped"
+)
+
+// severity is used to pick the worst status.
+func (s Status) severity() int {
+	switch s {
+	case StatusFail:
+		return 2
+	case StatusWarn:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// DegradedError is returned by a check function when the dependency works with reduced function, e.g. a high but
+// tolerable replication lag. It results in warn status even for a hard dependency.
+type DegradedError struct {
+	Err error
+}
+
+// NewDegradedError wraps err as a DegradedError.
+func NewDegradedError(err error) *DegradedError {
+	return &DegradedError{Err: err}
+}
+
+func (e *DegradedError) Error() string {
+	return "degraded: " + e.Err.Error()
+}
+
+func (e *DegradedError) Unwrap() error {
+	return e.Err
+}
+
+// StatusChangeEvent is passed to the OnStatusChange listeners when a dependency status or the overall service
+// status changes.
+type StatusChangeEvent struct {
+	// Dependency is the dependency name, empty when the overall service status changed.
+	Dependency     string
+	PreviousStatus Status
+	Status         Status
+	// Error is the last error message of a dependency which status is not pass.
+	Error          string
+	Timestamp      time.Time
+	HardDependency bool
+}
+
 // lastError holds last error information of a dependency
 type lastError struct {
 	Timestamp *time.Time `json:"timestamp"`
 	Message   string     `json:"message"`
+	// Stack is the stack of a panicking check, only reported when Config.Debug is set.
+	Stack string `json:"stack,omitempty"`
 }
 
-func (h *healthDependency) check() {
-	if h.checkFunc == nil {
-		return
-	}
+// panicError is the check error of a check function which panicked.
+type panicError struct {
+	value interface{}
+	stack []byte
+}
+
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// callCheckFunc calls the check function, recovering a panic into a panicError so that a faulty check does not
+// crash the service.
+func callCheckFunc(ctx context.Context, checkFunc CheckFuncWithContext) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &panicError{value: r, stack: debug.Stack()}
+		}
+	}()
+
+	return checkFunc(ctx)
+}
 
-	now := time.Now()
+// record updates the dependency health with a check result started at now. It returns the check error, including
+// a DegradedError.
+func (h *healthDependency) record(now time.Time, elapsed time.Duration, err error) error {
 	h.LastCall = &now
-	err := h.checkFunc()
-	if err != nil {
+	h.BlockedBy = ""
+	h.recordDuration(elapsed)
+	h.checks++
+
+	if err == nil && h.latencyThreshold > 0 && elapsed > h.latencyThreshold {
+		err = NewDegradedError(fmt.Errorf("check took %s, exceeding latency threshold %s", elapsed, h.latencyThreshold))
+	}
+
+	var degradedErr *DegradedError
+	h.degraded = errors.As(err, &degradedErr)
+	if h.degraded {
+		// a degraded dependency still works, hence counted as a success while keeping the error information
+		h.LastError = &lastError{Message: err.Error(), Timestamp: h.LastCall}
+	}
+
+	if err != nil && !h.degraded {
 		if h.LastError == nil {
 			h.LastError = &lastError{}
 		}
 		h.LastError.Message = err.Error()
 		h.LastError.Timestamp = h.LastCall
-		h.Healthy = false
-		return
+		h.LastError.Stack = ""
+		var panicErr *panicError
+		if errors.As(err, &panicErr) {
+			h.LastError.Stack = string(panicErr.stack)
+		}
+		h.checkFailures++
+		h.ConsecutiveFailures++
+		h.ConsecutiveSuccesses = 0
+		// only turn unhealthy after enough consecutive failures to suppress flapping
+		if h.ConsecutiveFailures >= h.failureThreshold {
+			h.Healthy = false
+		}
+		return err
+	}
+	h.ConsecutiveSuccesses++
+	h.ConsecutiveFailures = 0
+	if h.ConsecutiveSuccesses >= h.successThreshold {
+		h.Healthy = true
 	}
-	h.Healthy = true
 	h.LastKnownGoodCall = &now
 
-	return
+	return err
+}
+
+// runCheckFunc runs the check function and gives up waiting for it once the dependency timeout is exceeded,
+// so a hung check does not block the other checks and the health response. A panic is returned as the check error.
+// release is called once the check function returns.
+func (h *healthDependency) runCheckFunc(ctx context.Context, release func()) error {
+	if h.timeout <= 0 {
+		defer release()
+
+		return callCheckFunc(ctx, h.checkFunc)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	checkFunc := h.checkFunc
+	result := make(chan error, 1)
+	go func() {
+		defer release()
+		result <- callCheckFunc(ctx, checkFunc)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return h.timeoutError(ctx.Err())
+	}
+}
+
+func (h *healthDependency) timeoutError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", h.timeout)
+	}
+
+	return err
 }
 
 type CheckFunc func() error
 
-// healthOtherComponent health status other component of service.
-type healthOtherComponent struct {
-	Name    string `json:"name"`
-	Healthy bool   `json:"healthy"`
+// CheckFuncWithContext is a check function receiving the context of the check run. The context is canceled when
+// the check times out, the health request is canceled or the background check worker is stopped.
+type CheckFuncWithContext func(ctx context.Context) error
+
+// withContext adapts a CheckFunc into a CheckFuncWithContext ignoring the context.
+func (f CheckFunc) withContext() CheckFuncWithContext {
+	if f == nil {
+		return nil
+	}
+
+	return func(_ context.Context) error {
+		return f()
+	}
+}
+
+// Probe is a kind of Kubernetes probe a dependency health counts toward.
+type Probe string
+
+const (
+	// ProbeLiveness is used by the /livez endpoint.
+	ProbeLiveness Probe = "liveness"
+	// ProbeReadiness is used by the /readyz endpoint.
+	ProbeReadiness Probe = "readiness"
+	// ProbeStartup is used by the /startupz endpoint.
+	ProbeStartup Probe = "startup"
+)
+
+// DependencyOption configures a dependency when it is registered with AddHealthCheck or AddHardHealthCheck.
+type DependencyOption func(d *healthDependency)
+
+// WithProbes sets which probes the dependency health counts toward. When not set, a dependency only counts toward
+// the readiness probe, hence an unhealthy dependency takes the pod out of rotation instead of restarting it.
+// The /healthz endpoint always includes every dependency regardless of this option.
+func WithProbes(probes ...Probe) DependencyOption {
+	return func(d *healthDependency) {
+		d.probes = probes
+	}
+}
+
+// WithTags tags the dependency, e.g. storage or messaging. The /healthz response reports the health of every tag as
+// a group and the tag query parameter filters the response, e.g. /healthz?tag=storage.
+func WithTags(tags ...string) DependencyOption {
+	return func(d *healthDependency) {
+		d.Tags = tags
+	}
+}
+
+// WithDependsOn declares the upstream dependencies of the dependency, e.g. a network proxy. While an upstream
+// dependency is unhealthy, the dependency check is skipped and the dependency is reported as skipped, blocked by the
+// upstream dependency.
+//
+// The upstream dependencies are validated at registration: if any of them would create a cycle, e.g. a dependency
+// depending on itself, the whole list is discarded and the dependency is registered without upstream dependencies.
+// The discarded list is logged as an error and the dependency is reported without dependsOn.
+func WithDependsOn(names ...string) DependencyOption {
+	return func(d *healthDependency) {
+		d.DependsOn = names
+	}
+}
+
+// WithStaleAfter reports the dependency as stale when its health has not been updated within staleAfter, e.g. a
+// dependency updated using UpdateHealth which has not been called for a while. A stale dependency is considered
+// healthy if staleHealthy is set, otherwise unhealthy, there is no separate unknown status. The status change of a
+// dependency turning stale is reported to the watchers and the OnStatusChange listeners as soon as it turns stale.
+func WithStaleAfter(staleAfter time.Duration, staleHealthy bool) DependencyOption {
+	return func(d *healthDependency) {
+		d.staleAfter = staleAfter
+		d.staleHealthy = staleHealthy
+	}
+}
+
+// isStale returns whether the dependency health has not been updated within the stale duration.
+func (h *healthDependency) isStale(now time.Time) bool {
+	return h.staleAfter > 0 && h.LastCall != nil && now.Sub(*h.LastCall) > h.staleAfter
+}
+
+// nextSweep returns when the reported health of the dependency changes without any new result, if it does, i.e.
+// when it turns stale or when results age out of its error rate window.
+func (h *healthDependency) nextSweep(now time.Time) (time.Time, bool) {
+	var next time.Time
+	if h.staleAfter > 0 && h.LastCall != nil && !h.isStale(now) {
+		next = h.LastCall.Add(h.staleAfter + time.Nanosecond)
+	}
+
+	if h.errorRate != nil {
+		if expiry, ok := h.errorRate.nextExpiry(now); ok && (next.IsZero() || expiry.Before(next)) {
+			next = expiry
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+// WithTimeout sets how long the check runner waits for the dependency check before marking it unhealthy.
+// It overrides Config.CheckTimeout.
+func WithTimeout(timeout time.Duration) DependencyOption {
+	return func(d *healthDependency) {
+		d.timeout = timeout
+	}
+}
+
+// WithInterval sets the background check interval of the dependency. It overrides Config.BackgroundCheckInterval,
+// a zero or negative interval falls back to it.
+func WithInterval(interval time.Duration) DependencyOption {
+	return func(d *healthDependency) {
+		d.interval = interval
+	}
+}
+
+// WithLatencyThreshold marks the dependency degraded when a successful check takes longer than the threshold.
+func WithLatencyThreshold(threshold time.Duration) DependencyOption {
+	return func(d *healthDependency) {
+		d.latencyThreshold = threshold
+	}
+}
+
+// WithFailureThreshold sets how many consecutive check failures are needed before the dependency turns unhealthy.
+// Defaults to 1.
+func WithFailureThreshold(threshold int) DependencyOption {
+	return func(d *healthDependency) {
+		d.failureThreshold = threshold
+	}
+}
+
+// WithSuccessThreshold sets how many consecutive check successes are needed before the dependency turns healthy.
+// Defaults to 1.
+func WithSuccessThreshold(threshold int) DependencyOption {
+	return func(d *healthDependency) {
+		d.successThreshold = threshold
+	}
+}
+
+// status returns skipped for a dependency blocked by its upstream, warn for an unhealthy soft dependency or a degraded
+// dependency and fail for an unhealthy hard dependency.
+func (h *healthDependency) status() Status {
+	switch {
+	case h.BlockedBy != "":
+		return StatusSkipped
+	case !h.Healthy && h.HardDependency:
+		return StatusFail
+	case !h.Healthy, h.degraded:
+		return StatusWarn
+	default:
+		return StatusPass
+	}
+}
+
+// impact returns the status the dependency contributes to the service and group statuses, a skipped dependency
+// counts as fail when it is a hard dependency and warn otherwise.
+func (h *healthDependency) impact() Status {
+	status := h.status()
+	if status != StatusSkipped {
+		return status
+	}
+
+	if h.HardDependency {
+		return StatusFail
+	}
+
+	return StatusWarn
+}
+
+func (h *healthDependency) hasTag(tag string) bool {
+	// empty tag means every dependency
+	if tag == "" {
+		return true
+	}
+
+	for _, t := range h.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *healthDependency) hasProbe(probe Probe) bool {
+	// empty probe means every dependency, used by /healthz
+	if probe == "" {
+		return true
+	}
+
+	for _, p := range h.probes {
+		if p == probe {
+			return true
+		}
+	}
+
+	return false
+}
+
+// healthGroup is the health of the dependencies sharing a tag.
+type healthGroup struct {
+	Name         string   `json:"name"`
+	Healthy      bool     `json:"healthy"`
+	Status       Status   `json:"status"`
+	Dependencies []string `json:"dependencies"`
 }
 
 type response struct {
 	lock sync.RWMutex
 
-	Name         string                 `json:"name"`
-	Healthy      bool                   `json:"healthy"`
-	Dependencies []healthDependency     `json:"dependencies"`
-	Others       []healthOtherComponent `json:"others"`
+	Name         string             `json:"name"`
+	Healthy      bool               `json:"healthy"`
+	Status       Status             `json:"status"`
+	Draining     bool               `json:"draining,omitempty"`
+	CacheAge     *duration          `json:"cacheAge,omitempty"`
+	Dependencies []healthDependency `json:"dependencies"`
+	Groups       []healthGroup      `json:"groups"`
+	Rules        []ruleResult       `json:"rules,omitempty"`
 }
 
 func (h *response) appendHealthCheckDependency(dependency healthDependency) {
@@ -87,3 +498,36 @@ func (h *response) appendHealthCheckDependency(dependency healthDependency) {
 	defer h.lock.Unlock()
 	h.Dependencies = append(h.Dependencies, dependency)
 }
+
+// computeGroups computes the health of every tag of the dependencies, with the same rules as the service status.
+func (h *response) computeGroups() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	groups := make(map[string]*healthGroup)
+	names := make([]string, 0)
+	for _, dependency := range h.Dependencies {
+		for _, tag := range dependency.Tags {
+			group, exist := groups[tag]
+			if !exist {
+				group = &healthGroup{Name: tag, Status: StatusPass}
+				groups[tag] = group
+				names = append(names, tag)
+			}
+
+			group.Dependencies = append(group.Dependencies, dependency.Name)
+			if impact := dependency.impact(); impact.severity() > group.Status.severity() {
+				group.Status = impact
+			}
+		}
+	}
+
+	sort.Strings(names)
+	h.Groups = make([]healthGroup, 0, len(names))
+	for _, name := range names {
+		group := groups[name]
+		sort.Strings(group.Dependencies)
+		group.Healthy = group.Status != StatusFail
+		h.Groups = append(h.Groups, *group)
+	}
+}
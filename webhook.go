@@ -0,0 +1,231 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package healthcheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// WebhookSignatureHeader holds the hex encoded HMAC-SHA256 of the webhook body when WebhookConfig.Secret is set.
+	WebhookSignatureHeader = "X-Healthcheck-Signature"
+
+	DefaultWebhookQueueSize    = 100
+	DefaultWebhookMaxRetries   = 3
+	DefaultWebhookRetryBackoff = time.Second
+	DefaultWebhookTimeout      = 10 * time.Second
+)
+
+type WebhookConfig struct {
+	ServiceName string
+	URLs        []string
+	// Secret enables HMAC-SHA256 signing of the body, sent in WebhookSignatureHeader.
+	Secret string
+	// PayloadTemplate is a text/template executed with WebhookPayload. Defaults to WebhookPayload as JSON.
+	// Values are not escaped, use the json function to quote strings, e.g. {"text": {{json .Error}}}.
+	PayloadTemplate string
+	// QueueSize bounds the pending notifications, the newest notifications are dropped when the queue is full.
+	QueueSize int
+	// MaxRetries is the number of retries after a failed delivery. The delay starts at RetryBackoff and doubles.
+	// Zero defaults to DefaultWebhookMaxRetries, set a negative value to disable retries.
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// HTTPClient defaults to a client with DefaultWebhookTimeout.
+	HTTPClient *http.Client
+	// Logger defaults to the logrus standard logger.
+	Logger Logger
+}
+
+// WebhookPayload is the data of a webhook notification.
+type WebhookPayload struct {
+	ServiceName    string    `json:"serviceName"`
+	Dependency     string    `json:"dependency,omitempty"`
+	PreviousStatus Status    `json:"previousStatus"`
+	Status         Status    `json:"status"`
+	Error          string    `json:"error,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+	HardDependency bool      `json:"hardDependency"`
+}
+
+// WebhookNotifier posts status change events to the configured URLs. The events are queued, hence a slow
+// receiver never blocks the check runner.
+type WebhookNotifier struct {
+	config   WebhookConfig
+	template *template.Template
+	queue    chan StatusChangeEvent
+}
+
+func NewWebhookNotifier(config *WebhookConfig) (*WebhookNotifier, error) {
+	notifier := &WebhookNotifier{config: *config}
+
+	if config.PayloadTemplate != "" {
+		tmpl, err := template.New("payload").
+			Funcs(template.FuncMap{"json": jsonTemplateFunc}).
+			Parse(config.PayloadTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse webhook payload template: %v", err)
+		}
+		notifier.template = tmpl
+	}
+
+	if notifier.config.QueueSize <= 0 {
+		notifier.config.QueueSize = DefaultWebhookQueueSize
+	}
+
+	if notifier.config.MaxRetries < 0 {
+		notifier.config.MaxRetries = 0
+	} else if notifier.config.MaxRetries == 0 {
+		notifier.config.MaxRetries = DefaultWebhookMaxRetries
+	}
+
+	if notifier.config.RetryBackoff <= 0 {
+		notifier.config.RetryBackoff = DefaultWebhookRetryBackoff
+	}
+
+	if notifier.config.HTTPClient == nil {
+		notifier.config.HTTPClient = &http.Client{Timeout: DefaultWebhookTimeout}
+	}
+
+	if notifier.config.Logger == nil {
+		notifier.config.Logger = NewLogrusLogger(logrus.StandardLogger())
+	}
+
+	notifier.queue = make(chan StatusChangeEvent, notifier.config.QueueSize)
+
+	return notifier, nil
+}
+
+// Notify queues the event to be delivered, it can be registered directly using OnStatusChange.
+func (n *WebhookNotifier) Notify(evt StatusChangeEvent) {
+	select {
+	case n.queue <- evt:
+	default:
+		n.config.Logger.Warn("Webhook notification queue is full, dropping status change", "dependency", evt.Dependency)
+	}
+}
+
+// Start starts the delivery worker, it stops when the context is done.
+func (n *WebhookNotifier) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case evt := <-n.queue:
+				n.deliver(ctx, evt)
+			case <-ctx.Done():
+				n.config.Logger.Info("Webhook notifier stopped")
+				return
+			}
+		}
+	}()
+}
+
+func (n *WebhookNotifier) deliver(ctx context.Context, evt StatusChangeEvent) {
+	body, err := n.payload(evt)
+	if err != nil {
+		n.config.Logger.Error("Unable to build webhook payload", "dependency", evt.Dependency, "error", err)
+		return
+	}
+
+	for _, url := range n.config.URLs {
+		backoff := n.config.RetryBackoff
+		for attempt := 0; ; attempt++ {
+			err = n.post(ctx, url, body)
+			if err == nil || attempt >= n.config.MaxRetries {
+				break
+			}
+
+			select {
+			case <-time.After(backoff):
+				backoff *= 2
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err != nil {
+			n.config.Logger.Error("Unable to deliver webhook", "url", url, "dependency", evt.Dependency, "error", err)
+		}
+	}
+}
+
+func (n *WebhookNotifier) payload(evt StatusChangeEvent) ([]byte, error) {
+	payload := WebhookPayload{
+		ServiceName:    n.config.ServiceName,
+		Dependency:     evt.Dependency,
+		PreviousStatus: evt.PreviousStatus,
+		Status:         evt.Status,
+		Error:          evt.Error,
+		Timestamp:      evt.Timestamp,
+		HardDependency: evt.HardDependency,
+	}
+
+	if n.template == nil {
+		return json.Marshal(payload)
+	}
+
+	var body bytes.Buffer
+	if err := n.template.Execute(&body, payload); err != nil {
+		return nil, err
+	}
+
+	return body.Bytes(), nil
+}
+
+// jsonTemplateFunc encodes a template value as JSON, so that strings are quoted and escaped.
+func jsonTemplateFunc(value interface{}) (string, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if n.config.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.config.Secret))
+		mac.Write(body)
+		req.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
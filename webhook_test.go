@@ -0,0 +1,125 @@
+package healthcheck
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier(t *testing.T) {
+	var attempts int32
+	received := make(chan *http.Request, 4)
+	bodies := make(chan []byte, 4)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first attempt fails to test the retry
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookNotifier(&WebhookConfig{
+		ServiceName:  serviceName,
+		URLs:         []string{server.URL},
+		Secret:       "secret",
+		RetryBackoff: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifier.Start(ctx)
+
+	h := New(&Config{ServiceName: serviceName})
+	h.OnStatusChange(notifier.Notify)
+	h.AddHardHealthCheck("redis", testURL, nil)
+	require.NoError(t, h.UpdateHealth("redis", true, nil))
+	require.NoError(t, h.UpdateHealth("redis", false, &CheckError{Message: `dial tcp: lookup "redis"`}))
+
+	var req *http.Request
+	var body []byte
+	select {
+	case req = <-received:
+		body = <-bodies
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "webhook is not delivered")
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), req.Header.Get(WebhookSignatureHeader))
+
+	var payload WebhookPayload
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, serviceName, payload.ServiceName)
+	assert.Equal(t, "redis", payload.Dependency)
+	assert.Equal(t, StatusPass, payload.PreviousStatus)
+	assert.Equal(t, StatusFail, payload.Status)
+	assert.Equal(t, `dial tcp: lookup "redis"`, payload.Error)
+	assert.True(t, payload.HardDependency)
+}
+
+func TestWebhookNotifierPayloadTemplate(t *testing.T) {
+	notifier, err := NewWebhookNotifier(&WebhookConfig{
+		ServiceName:     serviceName,
+		PayloadTemplate: `{"text": "{{.ServiceName}}/{{.Dependency}} is {{.Status}}"}`,
+	})
+	require.NoError(t, err)
+
+	body, err := notifier.payload(StatusChangeEvent{Dependency: "redis", Status: StatusFail})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text": "test/redis is fail"}`, string(body))
+
+	notifier, err = NewWebhookNotifier(&WebhookConfig{
+		PayloadTemplate: `{"text": {{json .Error}}, "dependency": {{json .Dependency}}}`,
+	})
+	require.NoError(t, err)
+
+	body, err = notifier.payload(StatusChangeEvent{Dependency: "redis", Error: "dial tcp: lookup \"redis\"\nfailed"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text": "dial tcp: lookup \"redis\"\nfailed", "dependency": "redis"}`, string(body))
+
+	_, err = NewWebhookNotifier(&WebhookConfig{PayloadTemplate: "{{"})
+	assert.Error(t, err)
+}
+
+func TestWebhookNotifierQueueFull(t *testing.T) {
+	notifier, err := NewWebhookNotifier(&WebhookConfig{QueueSize: 1})
+	require.NoError(t, err)
+
+	// the worker is not started, so the queue is never drained
+	done := make(chan struct{})
+	go func() {
+		notifier.Notify(StatusChangeEvent{Dependency: "first"})
+		notifier.Notify(StatusChangeEvent{Dependency: "second"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		require.Fail(t, "notify is blocked by a full queue")
+	}
+
+	assert.Equal(t, "first", (<-notifier.queue).Dependency)
+}
@@ -0,0 +1,43 @@
+// Copyright 2021 AccelByte Inc
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zaplogger adapts a zap logger to the health check Logger.
+package zaplogger
+
+import (
+	"go.uber.org/zap"
+
+	healthcheck "github.com/AccelByte/healthcheck-go-sdk/v2"
+)
+
+type logger struct {
+	logger *zap.SugaredLogger
+}
+
+// New returns a health check Logger writing to a zap logger.
+func New(zapLogger *zap.Logger) healthcheck.Logger {
+	return &logger{logger: zapLogger.Sugar()}
+}
+
+func (l *logger) Info(msg string, keysAndValues ...interface{}) {
+	l.logger.Infow(msg, keysAndValues...)
+}
+
+func (l *logger) Warn(msg string, keysAndValues ...interface{}) {
+	l.logger.Warnw(msg, keysAndValues...)
+}
+
+func (l *logger) Error(msg string, keysAndValues ...interface{}) {
+	l.logger.Errorw(msg, keysAndValues...)
+}